@@ -5,13 +5,15 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/qjebbs/go-sqlb/dialect"
 	"github.com/qjebbs/go-sqlf/v4"
 )
 
 // clauseFrom represents a SQL FROM clause.
 type clauseFrom struct {
-	tables     []*fromTable          // the tables in order
-	tablesDict map[string]*fromTable // the from tables by alias
+	tables     []*fromTable           // the tables in order
+	tablesDict map[string]*fromTable  // the from tables by alias
+	indexHints map[string][]indexHint // the index hints by alias
 
 	explicitFrom bool    // whether From() has been called
 	errors       []error // errors during building
@@ -21,6 +23,7 @@ type clauseFrom struct {
 func newFrom() *clauseFrom {
 	return &clauseFrom{
 		tablesDict: make(map[string]*fromTable),
+		indexHints: make(map[string][]indexHint),
 	}
 }
 
@@ -30,6 +33,23 @@ type fromBuilderMeta struct {
 	DependOnMe []sqlf.Builder
 	Distinct   bool
 	HasGroupBy bool
+	// LockHints are the table hints for row locking, e.g. `WITH (UPDLOCK, ROWLOCK)`,
+	// applied to the LockTables, or all tables if LockTables is empty.
+	LockHints  []string
+	LockTables []Table
+}
+
+// lockHintsFor returns the lock hints for the table.
+func (m *fromBuilderMeta) lockHintsFor(t Table) []string {
+	if len(m.LockTables) == 0 {
+		return m.LockHints
+	}
+	for _, lt := range m.LockTables {
+		if lt.AppliedName() == t.AppliedName() {
+			return m.LockHints
+		}
+	}
+	return nil
 }
 
 // BuildRequired builds the FROM clause with required tables.
@@ -44,7 +64,8 @@ func (b *clauseFrom) BuildRequired(ctx Context, meta *fromBuilderMeta, deps *dep
 	pruning := pruningFromContext(ctx)
 	tables := make([]string, 0, len(b.tables))
 	if b.explicitFrom {
-		c, err := b.tables[0].BuildTo(ctx)
+		t := b.tables[0]
+		c, err := t.buildWithHints(ctx, b.indexHints[t.table.AppliedName()], meta.lockHintsFor(t.table))
 		if err != nil {
 			return "", fmt.Errorf("build FROM '%s': %w", b.tables[0].table, err)
 		}
@@ -54,7 +75,7 @@ func (b *clauseFrom) BuildRequired(ctx Context, meta *fromBuilderMeta, deps *dep
 		if pruning && b.shouldEliminateTable(meta, t, deps) {
 			continue
 		}
-		c, err := t.Builder.BuildTo(ctx)
+		c, err := t.buildWithHints(ctx, b.indexHints[t.table.AppliedName()], meta.lockHintsFor(t.table))
 		if err != nil {
 			return "", fmt.Errorf("build FROM '%s': %w", t.table, err)
 		}
@@ -100,8 +121,10 @@ func (b *clauseFrom) CollectDependencies(ctx Context, meta *fromBuilderMeta) (*d
 		// only respect the applied name of 'name', since it's
 		// unique and always valid in SelectBuilder
 		if t, ok := b.tablesDict[name.AppliedName()]; ok {
-			// required by FROM / JOIN
-			deps.SourceNames[t.table.Name] = true
+			if t.source == nil {
+				// required by FROM / JOIN
+				deps.SourceNames[t.table.Name] = true
+			}
 			if t.table != name {
 				// t.Name may be empty (from sqlb tag),
 				// or even wrong across builder scopes.
@@ -143,6 +166,20 @@ func (b *clauseFrom) collectDepsFromTable(ctx Context, meta *fromBuilderMeta, de
 			return err
 		}
 	}
+	// unresolved deps reported by subqueries, e.g. derived tables
+	for ft := range tables.OuterTables {
+		if _, ok := b.tablesDict[ft.AppliedName()]; !ok {
+			dep.OuterTables[ft] = true
+			continue
+		}
+		err := b.collectDepsFromTable(ctx, meta, dep, ft)
+		if err != nil {
+			return err
+		}
+	}
+	for name := range tables.SourceNames {
+		dep.SourceNames[name] = true
+	}
 	return nil
 }
 
@@ -159,6 +196,14 @@ func (b *clauseFrom) shouldEliminateTable(meta *fromBuilderMeta, t *fromTable, d
 	if !t.optional || dep == nil || dep.Tables == nil || dep.Tables[t.table] {
 		return false
 	}
+	if t.source != nil {
+		// derived tables are referenced by the alias only
+		for ref := range dep.Tables {
+			if ref.AppliedName() == t.table.AppliedName() {
+				return false
+			}
+		}
+	}
 	// automatic elimination for LEFT JOIN tables
 	if meta.Distinct || meta.HasGroupBy {
 		return true
@@ -185,12 +230,37 @@ func (b *clauseFrom) from(t Table) *clauseFrom {
 		b.pushError(fmt.Errorf("from table is empty"))
 		return b
 	}
-	table := &fromTable{
+	return b.setFrom(&fromTable{
 		table:          t,
-		Builder:        t.TableAs(),
 		optional:       false,
 		forceEliminate: false,
+	})
+}
+
+// FromSubquery set the from derived table, which is aliased by the applied name of alias.
+func (b *clauseFrom) FromSubquery(builder sqlf.Builder, alias Table) *clauseFrom {
+	table, err := newDerivedTable(builder, alias)
+	if err != nil {
+		b.pushError(fmt.Errorf("from subquery: %w", err))
+		return b
 	}
+	b.explicitFrom = true
+	return b.setFrom(table)
+}
+
+// FromFunc set the from table-valued function.
+func (b *clauseFrom) FromFunc(f TableFunc) *clauseFrom {
+	table, err := newFuncTable(f)
+	if err != nil {
+		b.pushError(fmt.Errorf("from function: %w", err))
+		return b
+	}
+	b.explicitFrom = true
+	return b.setFrom(table)
+}
+
+func (b *clauseFrom) setFrom(table *fromTable) *clauseFrom {
+	t := table.table
 	if len(b.tables) == 0 {
 		b.tables = append(b.tables, table)
 	} else {
@@ -214,20 +284,65 @@ func (b *clauseFrom) Join(joinStr string, t Table, on *sqlf.Fragment, optional,
 	// 	b.pushError(fmt.Errorf("table [%s AS %s] is already joined", t.Name, t.Alias))
 	// 	return b
 	// }
+	return b.addJoin(&fromTable{
+		table:          t,
+		joinStr:        joinStr,
+		on:             on,
+		optional:       optional,
+		forceEliminate: optional && forceEliminate,
+	})
+}
+
+// JoinSubquery append or replace a Join derived table,
+// which is aliased by the applied name of alias.
+func (b *clauseFrom) JoinSubquery(joinStr string, builder sqlf.Builder, alias Table, on *sqlf.Fragment, optional, forceEliminate bool) *clauseFrom {
+	table, err := newDerivedTable(builder, alias)
+	if err != nil {
+		b.pushError(fmt.Errorf("join subquery: %w", err))
+		return b
+	}
+	table.joinStr = joinStr
+	table.on = on
+	table.optional = optional
+	table.forceEliminate = optional && forceEliminate
+	return b.addJoin(table)
+}
+
+// JoinFunc append or replace a Join table-valued function.
+func (b *clauseFrom) JoinFunc(joinStr string, f TableFunc, on *sqlf.Fragment, optional, forceEliminate bool) *clauseFrom {
+	table, err := newFuncTable(f)
+	if err != nil {
+		b.pushError(fmt.Errorf("join function: %w", err))
+		return b
+	}
+	table.joinStr = joinStr
+	table.on = on
+	table.optional = optional
+	table.forceEliminate = optional && forceEliminate
+	return b.addJoin(table)
+}
+
+// JoinLateral append or replace a lateral join of the derived table,
+// where joinStr is "CROSS JOIN" or "LEFT JOIN", see buildLateral.
+func (b *clauseFrom) JoinLateral(joinStr string, builder sqlf.Builder, alias Table, optional, forceEliminate bool) *clauseFrom {
+	table, err := newDerivedTable(builder, alias)
+	if err != nil {
+		b.pushError(fmt.Errorf("lateral join: %w", err))
+		return b
+	}
+	table.joinStr = joinStr
+	table.lateral = true
+	table.optional = optional
+	table.forceEliminate = optional && forceEliminate
+	return b.addJoin(table)
+}
+
+func (b *clauseFrom) addJoin(table *fromTable) *clauseFrom {
+	t := table.table
 	if len(b.tables) == 0 {
 		// reserve the first alias for the main table
 		b.tables = append(b.tables, &fromTable{})
 	}
-	table := &fromTable{
-		table: t,
-		Builder: sqlf.F(
-			joinStr+" ? ?",
-			t.TableAs(),
-			sqlf.Prefix("ON", on),
-		),
-		optional:       optional,
-		forceEliminate: optional && forceEliminate,
-	}
 	if target, replacing := b.tablesDict[t.AppliedName()]; replacing {
 		*target = *table
 		return b
@@ -238,10 +353,169 @@ func (b *clauseFrom) Join(joinStr string, t Table, on *sqlf.Fragment, optional,
 }
 
 type fromTable struct {
-	sqlf.Builder
 	table          Table
-	optional       bool // only for auto-elimination of LEFT JOIN
-	forceEliminate bool // user declared to eliminate if not referenced
+	source         sqlf.Builder   // the aliased source of derived table, nil for base tables
+	lateral        bool           // whether the derived table is joined laterally
+	function       bool           // whether the source is a table-valued function
+	joinStr        string         // the JOIN keywords, empty for the FROM table
+	on             *sqlf.Fragment // the JOIN condition
+	optional       bool           // only for auto-elimination of LEFT JOIN
+	forceEliminate bool           // user declared to eliminate if not referenced
+}
+
+// newDerivedTable creates a derived table, which has the alias only,
+// so that it's never reported as a source name for CTEs.
+func newDerivedTable(builder sqlf.Builder, alias Table) (*fromTable, error) {
+	if builder == nil {
+		return nil, fmt.Errorf("subquery is nil")
+	}
+	if alias.AppliedName() == "" {
+		return nil, fmt.Errorf("derived table requires an alias")
+	}
+	table := Table{Alias: alias.AppliedName()}
+	return &fromTable{
+		table:  table,
+		source: sqlf.F("(?) AS ?", builder, table),
+	}, nil
+}
+
+// BuildTo implements sqlf.Builder
+func (t *fromTable) BuildTo(ctx sqlf.Context) (string, error) {
+	return t.buildWithHints(ctx, nil, nil)
+}
+
+// newFuncTable creates a derived table of the table-valued function.
+func newFuncTable(f TableFunc) (*fromTable, error) {
+	if f.Name == "" {
+		return nil, fmt.Errorf("function name is empty")
+	}
+	if f.Alias == "" {
+		return nil, fmt.Errorf("function %s requires an alias", f.Name)
+	}
+	table := Table{Alias: f.Alias}
+	return &fromTable{
+		table:    table,
+		source:   f.source(table),
+		function: true,
+	}, nil
+}
+
+// buildWithHints builds the table with the index hints and
+// the lock hints, e.g. `t WITH (INDEX(idx), UPDLOCK)`.
+func (t *fromTable) buildWithHints(ctx sqlf.Context, indexHints []indexHint, lockHints []string) (string, error) {
+	if t.lateral {
+		return t.buildLateral(ctx)
+	}
+	var table sqlf.Builder = t.table.TableAs()
+	if t.source != nil {
+		// hints are not applicable to derived tables
+		table = t.source
+	} else if len(indexHints)+len(lockHints) > 0 {
+		uCtx, err := contextUpgrade(ctx)
+		if err != nil {
+			return "", err
+		}
+		suffix, hints := tableIndexHints(uCtx, indexHints)
+		hints = append(hints, lockHints...)
+		if len(hints) > 0 {
+			suffix += " WITH (" + strings.Join(hints, ", ") + ")"
+		}
+		if suffix != "" {
+			table = sqlf.F("?"+suffix, table)
+		}
+	}
+	if t.joinStr == "" {
+		return table.BuildTo(ctx)
+	}
+	if t.function && t.joinStr == "CROSS JOIN" {
+		// table-valued functions referencing the preceding tables
+		// are joined with APPLY in some dialects, e.g. OPENJSON(t.col)
+		uCtx, err := contextUpgrade(ctx)
+		if err != nil {
+			return "", err
+		}
+		if uCtx.Dialect().Capabilities().LateralJoin == dialect.LateralJoinApply {
+			return sqlf.F("CROSS APPLY ?", table).BuildTo(ctx)
+		}
+	}
+	return sqlf.F(
+		t.joinStr+" ? ?",
+		table,
+		sqlf.Prefix("ON", t.on),
+	).BuildTo(ctx)
+}
+
+// buildLateral builds the lateral join of the derived table, e.g.:
+//
+//	CROSS JOIN LATERAL (SELECT ...) AS s
+//	LEFT JOIN LATERAL (SELECT ...) AS s ON TRUE
+//	CROSS APPLY (SELECT ...) AS s
+//	OUTER APPLY (SELECT ...) AS s
+func (t *fromTable) buildLateral(ctx sqlf.Context) (string, error) {
+	uCtx, err := contextUpgrade(ctx)
+	if err != nil {
+		return "", err
+	}
+	caps := uCtx.Dialect().Capabilities()
+	left := t.joinStr == "LEFT JOIN"
+	var tmpl string
+	switch caps.LateralJoin {
+	case dialect.LateralJoinLateral:
+		tmpl = t.joinStr + " LATERAL ?"
+		if left {
+			tmpl += " ON " + booleanPredicate(caps, true)
+		}
+	case dialect.LateralJoinApply:
+		tmpl = "CROSS APPLY ?"
+		if left {
+			tmpl = "OUTER APPLY ?"
+		}
+	default:
+		return "", fmt.Errorf("lateral join is not supported by %T", uCtx.BaseDialect())
+	}
+	return sqlf.F(tmpl, t.source).BuildTo(ctx)
+}
+
+// IndexHint adds an index hint for the table.
+func (b *clauseFrom) IndexHint(t Table, kind indexHintKind, indexes []string) *clauseFrom {
+	if len(indexes) == 0 {
+		b.pushError(fmt.Errorf("index hint of table '%s': no indexes", t))
+		return b
+	}
+	name := t.AppliedName()
+	b.indexHints[name] = append(b.indexHints[name], indexHint{
+		kind:    kind,
+		indexes: indexes,
+	})
+	return b
+}
+
+// IndexHintComments returns the index hints to be placed in the statement
+// hint comment, e.g. `INDEX("t" "idx")`, if the dialect hints indexes in comments.
+// The tables eliminated by BuildRequired are skipped.
+func (b *clauseFrom) IndexHintComments(ctx Context, meta *fromBuilderMeta, deps *dependencies) []string {
+	if ctx.Dialect().Capabilities().IndexHints != dialect.IndexHintComment {
+		return nil
+	}
+	pruning := pruningFromContext(ctx)
+	var r []string
+	for i, t := range b.tables {
+		if i > 0 && pruning && b.shouldEliminateTable(meta, t, deps) {
+			continue
+		}
+		for _, h := range b.indexHints[t.table.AppliedName()] {
+			name := "INDEX"
+			if h.kind == indexHintIgnore {
+				name = "NO_INDEX"
+			}
+			r = append(r, fmt.Sprintf(
+				"%s(%s %s)", name,
+				ctx.BaseDialect().QuoteIdentifier(t.table.AppliedName()),
+				quoteIndexes(ctx, h.indexes, " "),
+			))
+		}
+	}
+	return r
 }
 
 func (b *clauseFrom) pushError(err error) {
@@ -261,3 +535,60 @@ func (b *clauseFrom) anyError() error {
 	}
 	return errors.New(sb.String())
 }
+
+type indexHintKind int
+
+const (
+	indexHintUse indexHintKind = iota
+	indexHintForce
+	indexHintIgnore
+)
+
+// indexHint is an index hint of a table.
+type indexHint struct {
+	kind    indexHintKind
+	indexes []string
+}
+
+// tableIndexHints returns the index hints placed after the table, as a suffix
+// like ` USE INDEX (idx)`, or as table hints like `INDEX(idx)`.
+// The hints not supported by the dialect are dropped.
+func tableIndexHints(ctx Context, hints []indexHint) (suffix string, tableHints []string) {
+	switch ctx.Dialect().Capabilities().IndexHints {
+	case dialect.IndexHintClause:
+		for _, h := range hints {
+			keyword := "USE"
+			switch h.kind {
+			case indexHintForce:
+				keyword = "FORCE"
+			case indexHintIgnore:
+				keyword = "IGNORE"
+			}
+			suffix += " " + keyword + " INDEX (" + quoteIndexes(ctx, h.indexes, ", ") + ")"
+		}
+	case dialect.IndexHintTableHint:
+		for _, h := range hints {
+			if h.kind == indexHintIgnore {
+				continue
+			}
+			tableHints = append(tableHints, "INDEX("+quoteIndexes(ctx, h.indexes, ", ")+")")
+		}
+	case dialect.IndexHintIndexedBy:
+		for _, h := range hints {
+			// INDEXED BY fails the query if the index cannot be used,
+			// so it's for forcing a single index only.
+			if h.kind == indexHintForce && len(h.indexes) == 1 {
+				suffix = " INDEXED BY " + quoteIndexes(ctx, h.indexes, "")
+			}
+		}
+	}
+	return suffix, tableHints
+}
+
+func quoteIndexes(ctx Context, indexes []string, sep string) string {
+	quoted := make([]string, 0, len(indexes))
+	for _, idx := range indexes {
+		quoted = append(quoted, ctx.BaseDialect().QuoteIdentifier(idx))
+	}
+	return strings.Join(quoted, sep)
+}
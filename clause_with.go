@@ -21,10 +21,35 @@ type clauseWith struct {
 
 type cte struct {
 	sqlf.Builder
-	table   Table
-	columns []sqlf.Builder
-	types   []string
-	values  [][]any
+	table        Table
+	columns      []sqlf.Builder
+	types        []string
+	values       [][]any
+	recursive    bool
+	modifying    bool // data-modifying statement, never pruned
+	materialized CTEMaterialization
+}
+
+// CTEMaterialization is the materialization hint of CTE.
+type CTEMaterialization int
+
+// CTE materialization hints.
+const (
+	// CTEMaterializeDefault leaves the decision to the database.
+	CTEMaterializeDefault CTEMaterialization = iota
+	// CTEMaterialized forces the CTE to be computed once, e.g. `AS MATERIALIZED`.
+	CTEMaterialized
+	// CTENotMaterialized allows the CTE to be inlined into the query, e.g. `AS NOT MATERIALIZED`.
+	CTENotMaterialized
+)
+
+// CTEOptions is the options of common table expression.
+type CTEOptions struct {
+	// Columns are the explicit column names, e.g. `t (a, b) AS (...)`.
+	Columns []string
+	// Materialized is the materialization hint, which is ignored
+	// by the dialects without materialization control.
+	Materialized CTEMaterialization
 }
 
 // newWith creates a new With instance.
@@ -35,13 +60,24 @@ func newWith() *clauseWith {
 }
 
 // With adds a builder as common table expression,
-func (w *clauseWith) With(table Table, builder sqlf.Builder) *clauseWith {
+// with at most one CTEOptions.
+func (w *clauseWith) With(table Table, builder sqlf.Builder, opts ...CTEOptions) *clauseWith {
 	w.resetDepTablesCache()
 	t := table.WithAlias("")
 	cte := &cte{
 		table:   t,
 		Builder: builder,
 	}
+	switch builder.(type) {
+	case *InsertBuilder, *UpdateBuilder, *DeleteBuilder:
+		cte.modifying = true
+	}
+	if len(opts) > 0 {
+		cte.columns = util.Map(opts[0].Columns, func(c string) sqlf.Builder {
+			return sqlf.Identifier(c)
+		})
+		cte.materialized = opts[0].Materialized
+	}
 	w.ctes = append(w.ctes, cte)
 	w.ctesDict[t.Name] = cte
 	return w
@@ -64,14 +100,48 @@ func (w *clauseWith) WithValues(table Table, columns []string, types []string, v
 	return w
 }
 
+// WithRecursive adds a recursive common table expression,
+// whose recursive member references the CTE itself.
+func (w *clauseWith) WithRecursive(table Table, columns []string, anchor, recursive sqlf.Builder) *clauseWith {
+	w.resetDepTablesCache()
+	t := table.WithAlias("")
+	cte := &cte{
+		table: t,
+		columns: util.Map(columns, func(c string) sqlf.Builder {
+			return sqlf.Identifier(c)
+		}),
+		Builder:   sqlf.F("? UNION ALL ?", anchor, recursive),
+		recursive: true,
+	}
+	w.ctes = append(w.ctes, cte)
+	w.ctesDict[t.Name] = cte
+	return w
+}
+
 // BuildRequired builds the WITH clause including only the required CTEs.
-func (w *clauseWith) BuildRequired(ctx Context, required map[string]bool) (query string, err error) {
+//
+// The data-modifying CTEs are allowed in the top-level statement only,
+// i.e. not nested, since they are rejected in subqueries by the databases.
+func (w *clauseWith) BuildRequired(ctx Context, required map[string]bool, nested bool) (query string, err error) {
 	pruning := pruningFromContext(ctx)
 	cteClauses := make([]sqlf.Builder, 0, len(w.ctes))
+	recursive := false
 	for _, cte := range w.ctes {
-		if pruning && (required == nil || !required[cte.table.Name]) {
+		if pruning && !cte.modifying && (required == nil || !required[cte.table.Name]) {
 			continue
 		}
+		if cte.modifying && !ctx.Dialect().Capabilities().SupportsModifyingCTE {
+			return "", fmt.Errorf("With(%s): data-modifying CTE is not supported for dialect %T", cte.table.Name, ctx.BaseDialect())
+		}
+		if cte.modifying && nested {
+			return "", fmt.Errorf("With(%s): data-modifying CTE is allowed in the top-level statement only", cte.table.Name)
+		}
+		if cte.recursive {
+			if len(cte.columns) == 0 && ctx.Dialect().Capabilities().RequiresRecursiveColumnList {
+				return "", fmt.Errorf("WithRecursive(%s): columns cannot be empty", cte.table.Name)
+			}
+			recursive = true
+		}
 		builder := cte.Builder
 		if builder == nil && len(cte.columns) > 0 {
 			if len(cte.values) == 0 {
@@ -102,14 +172,23 @@ func (w *clauseWith) BuildRequired(ctx Context, required map[string]bool) (query
 			}), ", ")
 			builder = sqlf.Prefix("VALUES", builder)
 		}
+		as := "AS"
+		if ctx.Dialect().Capabilities().SupportsCTEMaterialized {
+			switch cte.materialized {
+			case CTEMaterialized:
+				as = "AS MATERIALIZED"
+			case CTENotMaterialized:
+				as = "AS NOT MATERIALIZED"
+			}
+		}
 		if len(cte.columns) == 0 {
 			cteClauses = append(cteClauses, sqlf.F(
-				"? AS (?)",
+				"? "+as+" (?)",
 				sqlf.Identifier(cte.table.Name), builder,
 			))
 		} else {
 			cteClauses = append(cteClauses, sqlf.F(
-				"? (?) AS (?)",
+				"? (?) "+as+" (?)",
 				sqlf.Identifier(cte.table.Name),
 				sqlf.Join(cte.columns, ", "),
 				builder,
@@ -119,7 +198,11 @@ func (w *clauseWith) BuildRequired(ctx Context, required map[string]bool) (query
 	if len(cteClauses) == 0 {
 		return "", nil
 	}
-	return sqlf.Prefix("WITH", sqlf.Join(cteClauses, ", ")).BuildTo(ctx)
+	keyword := "WITH"
+	if recursive && ctx.Dialect().Capabilities().RequiresRecursiveKeyword {
+		keyword = "WITH RECURSIVE"
+	}
+	return sqlf.Prefix(keyword, sqlf.Join(cteClauses, ", ")).BuildTo(ctx)
 }
 
 // CollectDependenciesForDeps collects the table dependencies for specific deps
@@ -134,8 +217,19 @@ func (w *clauseWith) CollectDependenciesForDeps(ctx Context, deps *dependencies)
 	// BUT this can cause problems which reporting source names
 	// that are not needed to.
 	for t := range deps.Tables {
+		if t.Name == "" {
+			// derived tables have the alias only
+			continue
+		}
 		required[t.Name] = true
 	}
+	for _, cte := range w.ctes {
+		// data-modifying CTEs are executed even if not referenced,
+		// so are the CTEs they depend on
+		if cte.modifying {
+			required[cte.table.Name] = true
+		}
+	}
 	for t := range deps.OuterTables {
 		// w has no knowledge of outer tables, report as unresolved
 		unresolved.OuterTables[t] = true
@@ -184,7 +278,8 @@ func (w *clauseWith) collectDepsFromCTE(ctx Context, deps map[string]bool, cte *
 	if err != nil {
 		return fmt.Errorf("collect dependencies of CTE %q: %w", cte.table, err)
 	}
-	// CTE can depend on other CTEs
+	// CTE can depend on other CTEs, and the recursive CTE
+	// depends on itself, which is skipped by deps[key] above
 	for t := range tables.Tables {
 		if cte, ok := w.ctesDict[t.Name]; ok {
 			err := w.collectDepsFromCTE(ctx, deps, cte)
@@ -0,0 +1,36 @@
+package sqlb
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/qjebbs/go-sqlf/v4"
+)
+
+var _ sqlf.Builder = (*coalescedColumn)(nil)
+
+// coalescedColumn is a column coalesced with the NullCoalesce of the dialect.
+type coalescedColumn struct {
+	column sqlf.Builder
+	goType reflect.Type
+}
+
+// BuildTo implements sqlf.Builder
+func (c *coalescedColumn) BuildTo(ctx sqlf.Context) (string, error) {
+	uCtx, err := contextUpgrade(ctx)
+	if err != nil {
+		return "", err
+	}
+	if c.goType == nil {
+		return "", fmt.Errorf("coalesce column: nil type")
+	}
+	coalesced, err := uCtx.Dialect().NullCoalesce(c.column, c.goType)
+	if err != nil {
+		return "", fmt.Errorf("coalesce column for %s: %w", c.goType, err)
+	}
+	if coalesced == nil {
+		// the type can handle NULLs natively
+		return c.column.BuildTo(ctx)
+	}
+	return coalesced.BuildTo(ctx)
+}
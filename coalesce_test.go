@@ -0,0 +1,261 @@
+package sqlb_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/qjebbs/go-sqlb"
+	"github.com/qjebbs/go-sqlb/dialect"
+	"github.com/qjebbs/go-sqlf/v4"
+)
+
+type testDecimal string
+
+func (testDecimal) SQLZero() any { return "0" }
+
+type testBlob []byte
+
+func TestSelectCoalescedInt(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	q := sqlb.NewSelectBuilder().
+		From(foo).
+		SelectCoalesced(foo.Column("bar"), reflect.TypeOf(int64(0)))
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT COALESCE("f"."bar", 0) FROM "foo" AS "f"`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectCoalescedDuration(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	q := sqlb.NewSelectBuilder().
+		From(foo).
+		SelectCoalesced(foo.Column("bar"), reflect.TypeOf(time.Duration(0)))
+	ctx := sqlb.NewContext(context.Background(), dialect.MySQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := "SELECT COALESCE(`f`.`bar`, 0) FROM `foo` AS `f`"
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectCoalescedBytes(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	q := sqlb.NewSelectBuilder().
+		From(foo).
+		SelectCoalesced(foo.Column("bar"), reflect.TypeOf([]byte(nil)))
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT "f"."bar" FROM "foo" AS "f"`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectCoalescedBlobPostgreSQL(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	q := sqlb.NewSelectBuilder().
+		From(foo).
+		SelectCoalesced(foo.Column("bar"), reflect.TypeOf(testBlob(nil)))
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT COALESCE("f"."bar", ''::bytea) FROM "foo" AS "f"`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectCoalescedBlobSQLite(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	q := sqlb.NewSelectBuilder().
+		From(foo).
+		SelectCoalesced(foo.Column("bar"), reflect.TypeOf(testBlob(nil)))
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLite{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT COALESCE("f"."bar", X'') FROM "foo" AS "f"`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectCoalescedBlobSQLServer(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	q := sqlb.NewSelectBuilder().
+		From(foo).
+		SelectCoalesced(foo.Column("bar"), reflect.TypeOf(testBlob(nil)))
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLServer{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT COALESCE([f].[bar], 0x) FROM [foo] AS [f]`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectCoalescedJSON(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	q := sqlb.NewSelectBuilder().
+		From(foo).
+		SelectCoalesced(foo.Column("bar"), reflect.TypeOf(json.RawMessage(nil)))
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT COALESCE("f"."bar", 'null') FROM "foo" AS "f"`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectCoalescedSQLZeroer(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	q := sqlb.NewSelectBuilder().
+		From(foo).
+		SelectCoalesced(foo.Column("bar"), reflect.TypeOf(testDecimal("")))
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT COALESCE("f"."bar", $1) FROM "foo" AS "f"`
+	wantArgs := []any{"0"}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectCoalescedOracleString(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	q := sqlb.NewSelectBuilder().
+		From(foo).
+		SelectCoalesced(foo.Column("bar"), reflect.TypeOf(""))
+	ctx := sqlb.NewContext(context.Background(), dialect.Oracle{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT NVL("f"."bar", '') FROM "foo" AS "f"`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectCoalescedOracleStringSentinel(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	q := sqlb.NewSelectBuilder().
+		From(foo).
+		SelectCoalesced(foo.Column("bar"), reflect.TypeOf(""))
+	ctx := sqlb.NewContext(context.Background(), dialect.Oracle{EmptyStringValue: " "})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT NVL("f"."bar", :1) FROM "foo" AS "f"`
+	wantArgs := []any{" "}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectCoalescedUnsupported(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	q := sqlb.NewSelectBuilder().
+		From(foo).
+		SelectCoalesced(foo.Column("bar"), reflect.TypeOf(testBlob(nil)))
+	ctx := sqlb.NewContext(context.Background(), dialect.Oracle{})
+	if _, _, err := q.Build(ctx); !errors.Is(err, dialect.ErrUnsupportedNullCoalesceType) {
+		t.Errorf("Oracle blob: want ErrUnsupportedNullCoalesceType, got %v", err)
+	}
+	q = sqlb.NewSelectBuilder().
+		From(foo).
+		SelectCoalesced(foo.Column("bar"), reflect.TypeOf(struct{}{}))
+	ctx = sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	if _, _, err := q.Build(ctx); !errors.Is(err, dialect.ErrUnsupportedNullCoalesceType) {
+		t.Errorf("struct: want ErrUnsupportedNullCoalesceType, got %v", err)
+	}
+}
+
+func TestSelectCoalescedElimination(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	bar := sqlb.NewTable("bar", "b")
+	b := sqlb.NewSelectBuilder().
+		From(foo).
+		LeftJoin(bar, sqlf.F("? = ?", bar.Column("foo_id"), foo.Column("id"))).
+		SelectCoalesced(bar.Column("amount"), reflect.TypeOf(0)).
+		EnableElimination()
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := b.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT COALESCE("b"."amount", 0) FROM "foo" AS "f" LEFT JOIN "bar" AS "b" ON "b"."foo_id" = "f"."id"`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
@@ -0,0 +1,175 @@
+package sqlb
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/qjebbs/go-sqlf/v4"
+)
+
+var _ sqlf.Builder = (*CompoundBuilder)(nil)
+var _ Builder = (*CompoundBuilder)(nil)
+
+// CompoundBuilder builds compound queries of set operations, whose ORDER BY /
+// LIMIT / OFFSET apply to the combined result, e.g.:
+//
+//	q1 := sqlb.NewSelectBuilder().Select(...).From(foo).OrderBy(...).Limit(3)
+//	q2 := sqlb.NewSelectBuilder().Select(...).From(bar)
+//	b := sqlb.NewCompoundBuilder(q1).UnionAll(q2).OrderBy(sqlf.F("id")).Limit(10)
+//	// (SELECT ... ORDER BY ... LIMIT 3) UNION ALL SELECT ... ORDER BY id LIMIT 10
+//
+// The operands with their own ORDER BY / LIMIT / OFFSET are parenthesized,
+// or wrapped as `SELECT * FROM (...)` for dialects that forbid parenthesized
+// operands, e.g. SQLite.
+type CompoundBuilder struct {
+	first    sqlf.Builder
+	operands *clauseList // set operations
+	order    *clauseList // order by columns of the combined result
+	limit    int64       // limit count of the combined result
+	offset   int64       // offset count of the combined result
+
+	debugger
+
+	pruning bool
+}
+
+// NewCompoundBuilder returns a new CompoundBuilder with the first operand.
+func NewCompoundBuilder(first sqlf.Builder) *CompoundBuilder {
+	return &CompoundBuilder{
+		first:    first,
+		operands: newPrefixedList("", " "),
+		order:    newPrefixedList("ORDER BY", ", "),
+	}
+}
+
+// Union unions other builders.
+func (b *CompoundBuilder) Union(builders ...sqlf.Builder) *CompoundBuilder {
+	return b.setOperation("UNION", false, builders)
+}
+
+// UnionAll unions other builders with 'UNION ALL'.
+func (b *CompoundBuilder) UnionAll(builders ...sqlf.Builder) *CompoundBuilder {
+	return b.setOperation("UNION", true, builders)
+}
+
+// Intersect intersects other builders.
+func (b *CompoundBuilder) Intersect(builders ...sqlf.Builder) *CompoundBuilder {
+	return b.setOperation("INTERSECT", false, builders)
+}
+
+// IntersectAll intersects other builders with 'INTERSECT ALL',
+// which is not supported by SQLite, SQLServer and Oracle.
+func (b *CompoundBuilder) IntersectAll(builders ...sqlf.Builder) *CompoundBuilder {
+	return b.setOperation("INTERSECT", true, builders)
+}
+
+// Except excepts other builders, which is built as 'MINUS' for Oracle.
+func (b *CompoundBuilder) Except(builders ...sqlf.Builder) *CompoundBuilder {
+	return b.setOperation("EXCEPT", false, builders)
+}
+
+// ExceptAll excepts other builders with 'EXCEPT ALL',
+// which is not supported by SQLite, SQLServer and Oracle.
+func (b *CompoundBuilder) ExceptAll(builders ...sqlf.Builder) *CompoundBuilder {
+	return b.setOperation("EXCEPT", true, builders)
+}
+
+func (b *CompoundBuilder) setOperation(op string, all bool, builders []sqlf.Builder) *CompoundBuilder {
+	for _, builder := range builders {
+		b.operands.Append(&setOperation{op: op, all: all, builder: builder})
+	}
+	return b
+}
+
+// OrderBy set the sorting order of the combined result, which usually
+// references the output columns by names or positions, e.g.:
+//
+//	b.OrderBy(sqlf.F("name DESC"))
+func (b *CompoundBuilder) OrderBy(order ...sqlf.Builder) *CompoundBuilder {
+	b.order.Append(order...)
+	return b
+}
+
+// Limit set the limit of the combined result.
+func (b *CompoundBuilder) Limit(limit int64) *CompoundBuilder {
+	if limit > 0 {
+		b.limit = limit
+	}
+	return b
+}
+
+// Offset set the offset of the combined result.
+func (b *CompoundBuilder) Offset(offset int64) *CompoundBuilder {
+	if offset > 0 {
+		b.offset = offset
+	}
+	return b
+}
+
+// Debug enables debug mode which prints the interpolated query to stdout.
+func (b *CompoundBuilder) Debug(name ...string) *CompoundBuilder {
+	b.debugger.Debug(name...)
+	return b
+}
+
+// EnableElimination enables JOIN / CTE elimination of the operands,
+// see SelectBuilder.EnableElimination.
+func (b *CompoundBuilder) EnableElimination() *CompoundBuilder {
+	b.pruning = true
+	return b
+}
+
+// Build builds the query.
+func (b *CompoundBuilder) Build(ctx Context) (query string, args []any, err error) {
+	return sqlf.Build(ctx, b)
+}
+
+// BuildTo implements sqlf.Builder
+func (b *CompoundBuilder) BuildTo(ctx sqlf.Context) (query string, err error) {
+	uCtx, err := contextUpgrade(ctx)
+	if err != nil {
+		return "", err
+	}
+	return b.buildInternal(uCtx)
+}
+
+func (b *CompoundBuilder) buildInternal(ctx Context) (string, error) {
+	if b == nil {
+		return "", nil
+	}
+	if b.first == nil {
+		return "", fmt.Errorf("compound query: no operands")
+	}
+	ctx = contextWithNestedStatement(ctx)
+	ctx, _ = decideContextPruning(ctx, b.pruning)
+	built := make([]string, 0)
+	first, err := buildSetOperand(ctx, b.first)
+	if err != nil {
+		return "", err
+	}
+	operands, err := buildSetOperations(ctx, first, b.operands.elements)
+	if err != nil {
+		return "", err
+	}
+	built = append(built, operands)
+	order, err := b.order.BuildTo(ctx)
+	if err != nil {
+		return "", err
+	}
+	if dependenciesFromContext(ctx) != nil {
+		// collecting dependencies only, which are
+		// reported by the operands themselves
+		return "", nil
+	}
+	if order != "" {
+		built = append(built, order)
+	}
+	page, err := buildPagination(ctx.Dialect().Capabilities(), order != "", false, b.limit, b.offset)
+	if err != nil {
+		return "", err
+	}
+	built = append(built, page.clauses...)
+	query := strings.Join(built, " ")
+	b.debugger.printIfDebug(ctx, query, ctx.Args())
+	return query, nil
+}
@@ -0,0 +1,247 @@
+package sqlb_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/qjebbs/go-sqlb"
+	"github.com/qjebbs/go-sqlb/dialect"
+	"github.com/qjebbs/go-sqlf/v4"
+)
+
+func TestCompoundBuilder(t *testing.T) {
+	var (
+		foo = sqlb.NewTable("foo", "f")
+		bar = sqlb.NewTable("bar", "b")
+	)
+	top := sqlb.NewSelectBuilder().
+		Select(foo.Column("id")).
+		From(foo).
+		OrderBy(sqlf.F("? DESC", foo.Column("score"))).
+		Limit(3)
+	rest := sqlb.NewSelectBuilder().
+		Select(bar.Column("id")).
+		From(bar).
+		Where(sqlf.F("? = ?", bar.Column("type"), "x"))
+	q := sqlb.NewCompoundBuilder(top).
+		UnionAll(rest).
+		OrderBy(sqlf.F("id")).
+		Limit(10).
+		Offset(20)
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `(SELECT "f"."id" FROM "foo" AS "f" ORDER BY "f"."score" DESC LIMIT 3) UNION ALL SELECT "b"."id" FROM "bar" AS "b" WHERE "b"."type" = $1 ORDER BY id LIMIT 10 OFFSET 20`
+	wantArgs := []any{"x"}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestCompoundBuilderMySQL(t *testing.T) {
+	var (
+		foo = sqlb.NewTable("foo", "f")
+		bar = sqlb.NewTable("bar", "b")
+	)
+	top := sqlb.NewSelectBuilder().
+		Select(foo.Column("id")).
+		From(foo).
+		OrderBy(sqlf.F("? DESC", foo.Column("score"))).
+		Limit(3)
+	rest := sqlb.NewSelectBuilder().
+		Select(bar.Column("id")).
+		From(bar).
+		Where(sqlf.F("? = ?", bar.Column("type"), "x"))
+	q := sqlb.NewCompoundBuilder(top).
+		UnionAll(rest).
+		OrderBy(sqlf.F("id")).
+		Limit(10).
+		Offset(20)
+	ctx := sqlb.NewContext(context.Background(), dialect.MySQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := "(SELECT `f`.`id` FROM `foo` AS `f` ORDER BY `f`.`score` DESC LIMIT 3) UNION ALL SELECT `b`.`id` FROM `bar` AS `b` WHERE `b`.`type` = ? ORDER BY id LIMIT 10 OFFSET 20"
+	wantArgs := []any{"x"}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestCompoundBuilderSQLite(t *testing.T) {
+	var (
+		foo = sqlb.NewTable("foo", "f")
+		bar = sqlb.NewTable("bar", "b")
+	)
+	top := sqlb.NewSelectBuilder().
+		Select(foo.Column("id")).
+		From(foo).
+		OrderBy(sqlf.F("? DESC", foo.Column("score"))).
+		Limit(3)
+	rest := sqlb.NewSelectBuilder().
+		Select(bar.Column("id")).
+		From(bar).
+		Where(sqlf.F("? = ?", bar.Column("type"), "x"))
+	q := sqlb.NewCompoundBuilder(top).
+		UnionAll(rest).
+		OrderBy(sqlf.F("id")).
+		Limit(10).
+		Offset(20)
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLite{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT * FROM (SELECT "f"."id" FROM "foo" AS "f" ORDER BY "f"."score" DESC LIMIT 3) UNION ALL SELECT "b"."id" FROM "bar" AS "b" WHERE "b"."type" = ? ORDER BY id LIMIT 10 OFFSET 20`
+	wantArgs := []any{"x"}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestCompoundBuilderNested(t *testing.T) {
+	var (
+		foo = sqlb.NewTable("foo", "f")
+		bar = sqlb.NewTable("bar", "b")
+		baz = sqlb.NewTable("baz", "z")
+	)
+	inner := sqlb.NewCompoundBuilder(
+		sqlb.NewSelectBuilder().Select(foo.Column("id")).From(foo),
+	).Union(
+		sqlb.NewSelectBuilder().Select(bar.Column("id")).From(bar),
+	)
+	b := sqlb.NewCompoundBuilder(inner).
+		Except(sqlb.NewSelectBuilder().Select(baz.Column("id")).From(baz))
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := b.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `(SELECT "f"."id" FROM "foo" AS "f" UNION SELECT "b"."id" FROM "bar" AS "b") EXCEPT SELECT "z"."id" FROM "baz" AS "z"`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestCompoundBuilderAsSubquery(t *testing.T) {
+	var (
+		foo   = sqlb.NewTable("foo", "f")
+		bar   = sqlb.NewTable("bar", "b")
+		users = sqlb.NewTable("users", "u")
+		cte   = sqlb.NewTable("cte")
+	)
+	ids := sqlb.NewCompoundBuilder(
+		sqlb.NewSelectBuilder().Select(bar.Column("foo_id")).From(bar),
+	).Union(
+		sqlb.NewSelectBuilder().Select(cte.Column("foo_id")).From(cte),
+	)
+	q := sqlb.NewSelectBuilder().
+		EnableElimination().
+		With(cte, sqlf.F("SELECT 1 AS foo_id")).
+		Distinct().
+		Select(foo.Column("id")).
+		From(foo).
+		LeftJoin(users, sqlf.F("? = ?", users.Column("id"), foo.Column("user_id"))).
+		Where(sqlf.F("? IN (?)", foo.Column("id"), ids))
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the CTE referenced by the operand is kept
+	wantQuery := `WITH "cte" AS (SELECT 1 AS foo_id) SELECT DISTINCT "f"."id" FROM "foo" AS "f" WHERE "f"."id" IN (SELECT "b"."foo_id" FROM "bar" AS "b" UNION SELECT "cte"."foo_id" FROM "cte")`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectBuilderUnionScopedClauses(t *testing.T) {
+	var (
+		foo = sqlb.NewTable("foo", "f")
+		bar = sqlb.NewTable("bar", "b")
+	)
+	b := sqlb.NewSelectBuilder().
+		Select(foo.Column("id")).
+		From(foo).
+		OrderBy(foo.Column("id")).
+		Limit(1).
+		Union(
+			sqlb.NewSelectBuilder().Select(bar.Column("id")).From(bar).Limit(2),
+		)
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := b.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `(SELECT "f"."id" FROM "foo" AS "f" ORDER BY "f"."id" LIMIT 1) UNION (SELECT "b"."id" FROM "bar" AS "b" LIMIT 2)`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestCompoundBuilderLockedOperand(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	bar := sqlb.NewTable("bar", "b")
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	q := sqlb.NewCompoundBuilder(sqlb.NewSelectBuilder().Select(foo.Column("id")).From(foo).ForUpdate()).
+		Union(sqlb.NewSelectBuilder().Select(bar.Column("id")).From(bar))
+	if _, _, err := q.Build(ctx); err == nil {
+		t.Error("first operand: want error, got nil")
+	}
+	q = sqlb.NewCompoundBuilder(sqlb.NewSelectBuilder().Select(foo.Column("id")).From(foo)).
+		Union(sqlb.NewSelectBuilder().Select(bar.Column("id")).From(bar).ForUpdate())
+	if _, _, err := q.Build(ctx); err == nil {
+		t.Error("union operand: want error, got nil")
+	}
+}
+
+func TestCompoundBuilderSQLServer(t *testing.T) {
+	var (
+		foo = sqlb.NewTable("foo", "f")
+		bar = sqlb.NewTable("bar", "b")
+	)
+	q := sqlb.NewCompoundBuilder(sqlb.NewSelectBuilder().Select(foo.Column("id")).From(foo)).
+		UnionAll(sqlb.NewSelectBuilder().Select(bar.Column("id")).From(bar)).
+		Limit(10)
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLServer{})
+	if _, _, err := q.Build(ctx); err == nil {
+		t.Error("unordered: want error, got nil")
+	}
+	gotQuery, gotArgs, err := q.OrderBy(sqlf.F("id")).Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT [f].[id] FROM [foo] AS [f] UNION ALL SELECT [b].[id] FROM [bar] AS [b] ORDER BY id OFFSET 0 ROWS FETCH NEXT 10 ROWS ONLY`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
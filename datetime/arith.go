@@ -0,0 +1,146 @@
+package datetime
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/qjebbs/go-sqlb/dialect"
+	"github.com/qjebbs/go-sqlf/v4"
+)
+
+// microsecond is the internal unit for durations of fractional seconds.
+const microsecond Unit = -1
+
+// Add adds the duration d to the time t, e.g.:
+//
+//	datetime.Add(t.Column("created_at"), 2*time.Hour)
+//	// PostgreSQL: ("t"."created_at" + INTERVAL '7200 second')
+//	// MySQL:      DATE_ADD(`t`.`created_at`, INTERVAL 7200 SECOND)
+//	// SQLServer:  DATEADD(second, 7200, [t].[created_at])
+func Add(t any, d time.Duration) sqlf.Builder {
+	if d%time.Second == 0 {
+		return AddInterval(t, int64(d/time.Second), Second)
+	}
+	return AddInterval(t, d.Microseconds(), microsecond)
+}
+
+// Sub subtracts the duration d from the time t.
+func Sub(t any, d time.Duration) sqlf.Builder {
+	return Add(t, -d)
+}
+
+// Ago returns the timestamp of d before now, e.g.:
+//
+//	b.WhereGreaterThan(t.Column("created_at"), datetime.Ago(7*24*time.Hour))
+func Ago(d time.Duration) sqlf.Builder {
+	return Sub(Now(), d)
+}
+
+// AddInterval adds n calendar units to the time t, which respects
+// the variable length of months and years, see dialect.IntervalStyle, e.g.:
+//
+//	datetime.AddInterval(t.Column("created_at"), -1, datetime.Month)
+func AddInterval(t any, n int64, unit Unit) sqlf.Builder {
+	return sqlf.Func(func(ctx sqlf.Context) (string, error) {
+		d := dialectOf(ctx)
+		if !unit.valid() && unit != microsecond {
+			return "", errUnsupportedUnit("AddInterval", unit, d)
+		}
+		switch d.Capabilities().Interval {
+		case dialect.IntervalString:
+			n, name := n, unit.String()
+			switch unit {
+			case microsecond:
+				name = "microsecond"
+			case Quarter:
+				n, name = n*3, "month"
+			}
+			return sqlf.F(fmt.Sprintf("(? + INTERVAL '%d %s')", n, name), arg(t)).BuildTo(ctx)
+		case dialect.IntervalDateAdd:
+			name := strings.ToUpper(unit.String())
+			if unit == microsecond {
+				name = "MICROSECOND"
+			}
+			return sqlf.F(fmt.Sprintf("DATE_ADD(?, INTERVAL %d %s)", n, name), arg(t)).BuildTo(ctx)
+		case dialect.IntervalModifier:
+			return addSQLite(ctx, t, n, unit)
+		case dialect.IntervalDatePart:
+			name := unit.String()
+			if unit == microsecond {
+				name = "microsecond"
+				if n > math.MaxInt32 || n < math.MinInt32 {
+					// DATEADD accepts int only
+					n, name = n/1000, "millisecond"
+				}
+			}
+			return sqlf.F(fmt.Sprintf("DATEADD(%s, %d, ?)", name, n), arg(t)).BuildTo(ctx)
+		case dialect.IntervalNumToDS:
+			switch unit {
+			case microsecond:
+				return sqlf.F(fmt.Sprintf("(? + NUMTODSINTERVAL(%s, 'SECOND'))", seconds(n)), arg(t)).BuildTo(ctx)
+			case Week:
+				return sqlf.F(fmt.Sprintf("(? + NUMTODSINTERVAL(%d, 'DAY'))", n*7), arg(t)).BuildTo(ctx)
+			case Month, Quarter, Year:
+				return sqlf.F(fmt.Sprintf("ADD_MONTHS(?, %d)", n*monthsOf(unit)), arg(t)).BuildTo(ctx)
+			default:
+				return sqlf.F(fmt.Sprintf("(? + NUMTODSINTERVAL(%d, '%s'))", n, strings.ToUpper(unit.String())), arg(t)).BuildTo(ctx)
+			}
+		case dialect.IntervalStandard:
+			switch unit {
+			case microsecond:
+				return sqlf.F(fmt.Sprintf("(? + INTERVAL '%s' SECOND)", seconds(n)), arg(t)).BuildTo(ctx)
+			case Week:
+				n, unit = n*7, Day
+			case Quarter:
+				n, unit = n*3, Month
+			}
+			return sqlf.F(fmt.Sprintf("(? + INTERVAL '%d' %s)", n, strings.ToUpper(unit.String())), arg(t)).BuildTo(ctx)
+		default:
+			return "", errUnsupported("AddInterval", d)
+		}
+	})
+}
+
+func addSQLite(ctx sqlf.Context, t any, n int64, unit Unit) (string, error) {
+	switch unit {
+	case microsecond:
+		// keep the fractional seconds with %f
+		return sqlf.F(
+			fmt.Sprintf("strftime('%%Y-%%m-%%d %%H:%%M:%%f', ?, '%s seconds')", signed(seconds(n))),
+			arg(t),
+		).BuildTo(ctx)
+	case Week:
+		n, unit = n*7, Day
+	case Quarter:
+		n, unit = n*3, Month
+	}
+	return sqlf.F(
+		fmt.Sprintf("datetime(?, '%s %ss')", signed(strconv.FormatInt(n, 10)), unit),
+		arg(t),
+	).BuildTo(ctx)
+}
+
+// seconds formats microseconds as decimal seconds.
+func seconds(micro int64) string {
+	return strconv.FormatFloat(float64(micro)/1e6, 'f', -1, 64)
+}
+
+func signed(n string) string {
+	if strings.HasPrefix(n, "-") {
+		return n
+	}
+	return "+" + n
+}
+
+func monthsOf(u Unit) int64 {
+	switch u {
+	case Quarter:
+		return 3
+	case Year:
+		return 12
+	}
+	return 1
+}
@@ -0,0 +1,95 @@
+// Package datetime provides portable SQL date / time functions and interval
+// arithmetic, which choose their rendering according to the dialect of the
+// building context.
+//
+// The time arguments are either sqlf.Builder (e.g. table columns) or ordinary
+// values to be bound as args, and table references in them are reported to
+// the dependency tracking of sqlb builders as usual:
+//
+//	orders := sqlb.NewTable("orders", "o")
+//	day := datetime.Trunc(datetime.Day, orders.Column("created_at"))
+//	b.Select(day, sqlf.F("COUNT(*)")).
+//		From(orders).
+//		WhereGreaterThan(orders.Column("created_at"), datetime.Ago(7*24*time.Hour)).
+//		GroupBy(day)
+package datetime
+
+import (
+	"fmt"
+
+	"github.com/qjebbs/go-sqlb/dialect"
+	"github.com/qjebbs/go-sqlf/v4"
+)
+
+// Unit is the calendar unit for date / time functions.
+type Unit int
+
+// Calendar units.
+const (
+	Second Unit = iota
+	Minute
+	Hour
+	Day
+	// Week is the ISO week, which starts on Monday.
+	Week
+	Month
+	Quarter
+	Year
+)
+
+// String returns the lower case name of the unit.
+func (u Unit) String() string {
+	switch u {
+	case Second:
+		return "second"
+	case Minute:
+		return "minute"
+	case Hour:
+		return "hour"
+	case Day:
+		return "day"
+	case Week:
+		return "week"
+	case Month:
+		return "month"
+	case Quarter:
+		return "quarter"
+	case Year:
+		return "year"
+	}
+	return fmt.Sprintf("Unit(%d)", int(u))
+}
+
+func (u Unit) valid() bool {
+	return u >= Second && u <= Year
+}
+
+// Now returns the current timestamp.
+func Now() sqlf.Builder {
+	return sqlf.F("CURRENT_TIMESTAMP")
+}
+
+// dialectOf returns the sqlb dialect of the context,
+// falling back to AnsiSQL if it cannot be upgraded.
+func dialectOf(ctx sqlf.Context) dialect.Dialect {
+	if d, ok := dialect.Upgrade(ctx.BaseDialect()); ok {
+		return d
+	}
+	return dialect.AnsiSQL{}
+}
+
+// arg converts a mixed argument to a builder.
+func arg(a any) sqlf.Builder {
+	if b, ok := a.(sqlf.Builder); ok {
+		return b
+	}
+	return sqlf.F("?", a)
+}
+
+func errUnsupported(fn string, d dialect.Dialect) error {
+	return fmt.Errorf("datetime: %s is not supported for dialect %T", fn, d)
+}
+
+func errUnsupportedUnit(fn string, u Unit, d dialect.Dialect) error {
+	return fmt.Errorf("datetime: %s by %s is not supported for dialect %T", fn, u, d)
+}
@@ -0,0 +1,506 @@
+package datetime_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/qjebbs/go-sqlb"
+	"github.com/qjebbs/go-sqlb/datetime"
+	"github.com/qjebbs/go-sqlb/dialect"
+	"github.com/qjebbs/go-sqlf/v4"
+)
+
+func TestAgoPostgreSQL(t *testing.T) {
+	b := datetime.Ago(7 * 24 * time.Hour)
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `(CURRENT_TIMESTAMP + INTERVAL '-604800 second')`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestAgoMySQL(t *testing.T) {
+	b := datetime.Ago(7 * 24 * time.Hour)
+	ctx := sqlb.NewContext(context.Background(), dialect.MySQL{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `DATE_ADD(CURRENT_TIMESTAMP, INTERVAL -604800 SECOND)`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestAgoSQLite(t *testing.T) {
+	b := datetime.Ago(7 * 24 * time.Hour)
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLite{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `datetime(CURRENT_TIMESTAMP, '-604800 seconds')`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestAgoSQLServer(t *testing.T) {
+	b := datetime.Ago(7 * 24 * time.Hour)
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLServer{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `DATEADD(second, -604800, CURRENT_TIMESTAMP)`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestAgoOracle(t *testing.T) {
+	b := datetime.Ago(7 * 24 * time.Hour)
+	ctx := sqlb.NewContext(context.Background(), dialect.Oracle{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `(CURRENT_TIMESTAMP + NUMTODSINTERVAL(-604800, 'SECOND'))`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestAddPostgreSQL(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	b := datetime.Add(foo.Column("created_at"), 1500*time.Millisecond)
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `("f"."created_at" + INTERVAL '1500000 microsecond')`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestAddSQLite(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	b := datetime.Add(foo.Column("created_at"), 1500*time.Millisecond)
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLite{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `strftime('%Y-%m-%d %H:%M:%f', "f"."created_at", '+1.5 seconds')`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestAddOracle(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	b := datetime.Add(foo.Column("created_at"), 1500*time.Millisecond)
+	ctx := sqlb.NewContext(context.Background(), dialect.Oracle{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `("f"."created_at" + NUMTODSINTERVAL(1.5, 'SECOND'))`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestAddIntervalPostgreSQL(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	b := datetime.AddInterval(foo.Column("created_at"), 1, datetime.Quarter)
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `("f"."created_at" + INTERVAL '3 month')`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestAddIntervalMySQL(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	b := datetime.AddInterval(foo.Column("created_at"), 1, datetime.Quarter)
+	ctx := sqlb.NewContext(context.Background(), dialect.MySQL{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := "DATE_ADD(`f`.`created_at`, INTERVAL 1 QUARTER)"
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestAddIntervalSQLite(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	b := datetime.AddInterval(foo.Column("created_at"), 1, datetime.Quarter)
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLite{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `datetime("f"."created_at", '+3 months')`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestAddIntervalOracle(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	b := datetime.AddInterval(foo.Column("created_at"), 1, datetime.Quarter)
+	ctx := sqlb.NewContext(context.Background(), dialect.Oracle{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `ADD_MONTHS("f"."created_at", 3)`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestTruncPostgreSQL(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	b := datetime.Trunc(datetime.Month, foo.Column("created_at"))
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `DATE_TRUNC('month', "f"."created_at")`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestTruncMySQL(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	b := datetime.Trunc(datetime.Month, foo.Column("created_at"))
+	ctx := sqlb.NewContext(context.Background(), dialect.MySQL{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := "CAST(DATE_FORMAT(`f`.`created_at`, '%Y-%m-01 00:00:00') AS DATETIME)"
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestTruncSQLite(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	b := datetime.Trunc(datetime.Month, foo.Column("created_at"))
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLite{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `datetime("f"."created_at", 'start of month')`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestTruncSQLServer(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	b := datetime.Trunc(datetime.Month, foo.Column("created_at"))
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLServer{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `DATEADD(month, DATEDIFF(month, 0, [f].[created_at]), 0)`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestTruncOracle(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	b := datetime.Trunc(datetime.Month, foo.Column("created_at"))
+	ctx := sqlb.NewContext(context.Background(), dialect.Oracle{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `TRUNC("f"."created_at", 'MM')`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestExtractYearPostgreSQL(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	b := datetime.Extract(datetime.Year, foo.Column("created_at"))
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `EXTRACT(YEAR FROM "f"."created_at")`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestExtractYearSQLite(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	b := datetime.Extract(datetime.Year, foo.Column("created_at"))
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLite{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `CAST(strftime('%Y', "f"."created_at") AS INTEGER)`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestExtractYearSQLServer(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	b := datetime.Extract(datetime.Year, foo.Column("created_at"))
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLServer{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `DATEPART(year, [f].[created_at])`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestConvertTimeZonePostgreSQL(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	b := datetime.ConvertTimeZone(foo.Column("created_at"), "UTC", "Asia/Shanghai")
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `(("f"."created_at" AT TIME ZONE $1) AT TIME ZONE $2)`
+	wantArgs := []any{"UTC", "Asia/Shanghai"}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestConvertTimeZoneMySQL(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	b := datetime.ConvertTimeZone(foo.Column("created_at"), "UTC", "Asia/Shanghai")
+	ctx := sqlb.NewContext(context.Background(), dialect.MySQL{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := "CONVERT_TZ(`f`.`created_at`, ?, ?)"
+	wantArgs := []any{"UTC", "Asia/Shanghai"}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+// wrappedMySQL is a custom dialect embedding a built-in one.
+type wrappedMySQL struct {
+	dialect.MySQL
+}
+
+func TestTruncWrappedDialect(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	ctx := sqlb.NewContext(context.Background(), wrappedMySQL{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, datetime.Trunc(datetime.Month, foo.Column("created_at")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := "CAST(DATE_FORMAT(`f`.`created_at`, '%Y-%m-01 00:00:00') AS DATETIME)"
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestDateTimeUnsupported(t *testing.T) {
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLite{})
+	_, _, err := sqlf.Build(ctx, datetime.ConvertTimeZone(datetime.Now(), "UTC", "Asia/Shanghai"))
+	if err == nil {
+		t.Error("want error, got nil")
+	}
+	_, _, err = sqlf.Build(ctx, datetime.Trunc(datetime.Unit(100), datetime.Now()))
+	if err == nil {
+		t.Error("want error, got nil")
+	}
+}
+
+func TestDateTimeGroupBy(t *testing.T) {
+	var (
+		orders = sqlb.NewTable("orders", "o")
+		users  = sqlb.NewTable("users", "u")
+	)
+	day := datetime.Trunc(datetime.Day, users.Column("created_at"))
+	b := sqlb.NewSelectBuilder().
+		EnableElimination().
+		Select(day, sqlf.F("COUNT(*)")).
+		From(orders).
+		LeftJoin(users, sqlf.F("? = ?", users.Column("id"), orders.Column("user_id"))).
+		WhereGreaterThan(orders.Column("created_at"), datetime.Ago(24*time.Hour)).
+		GroupBy(day)
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := b.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT DATE_TRUNC('day', "u"."created_at"), COUNT(*) FROM "orders" AS "o" LEFT JOIN "users" AS "u" ON "u"."id" = "o"."user_id" WHERE "o"."created_at" > (CURRENT_TIMESTAMP + INTERVAL '-86400 second') GROUP BY DATE_TRUNC('day', "u"."created_at")`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestExtractWeekMySQL(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	ctx := sqlb.NewContext(context.Background(), dialect.MySQL{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, datetime.Extract(datetime.Week, foo.Column("created_at")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := "WEEK(`f`.`created_at`, 3)"
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestExtractWeekSQLite(t *testing.T) {
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLite{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, datetime.Extract(datetime.Week, "2021-01-03"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := "((CAST(strftime('%j', ?, '-3 days', 'weekday 4') AS INTEGER) - 1) / 7 + 1)"
+	wantArgs := []any{"2021-01-03"}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestExtractWeekUnsupported(t *testing.T) {
+	ctx := sqlb.NewContext(context.Background(), dialect.AnsiSQL{})
+	if _, _, err := sqlf.Build(ctx, datetime.Extract(datetime.Week, datetime.Now())); err == nil {
+		t.Error("want error, got nil")
+	}
+}
@@ -0,0 +1,214 @@
+package datetime
+
+import (
+	"strings"
+
+	"github.com/qjebbs/go-sqlb/dialect"
+	"github.com/qjebbs/go-sqlf/v4"
+)
+
+// Trunc truncates the time t to the start of the unit, e.g.:
+//
+//	datetime.Trunc(datetime.Day, t.Column("created_at"))
+//	// PostgreSQL: DATE_TRUNC('day', "t"."created_at")
+//	// MySQL:      CAST(DATE_FORMAT(`t`.`created_at`, '%Y-%m-%d 00:00:00') AS DATETIME)
+//	// SQLite:     datetime("t"."created_at", 'start of day')
+//	// SQLServer:  DATEADD(day, DATEDIFF(day, 0, [t].[created_at]), 0)
+//	// Oracle:     TRUNC("t"."created_at", 'DD')
+//
+// It's not supported by AnsiSQL, see dialect.DateTruncStyle.
+func Trunc(unit Unit, t any) sqlf.Builder {
+	return sqlf.Func(func(ctx sqlf.Context) (string, error) {
+		d := dialectOf(ctx)
+		if !unit.valid() {
+			return "", errUnsupportedUnit("Trunc", unit, d)
+		}
+		var tmpl string
+		switch d.Capabilities().DateTrunc {
+		case dialect.DateTruncFunction:
+			tmpl = "DATE_TRUNC('" + unit.String() + "', $1)"
+		case dialect.DateTruncFormat:
+			switch unit {
+			case Second:
+				tmpl = "CAST(DATE_FORMAT($1, '%Y-%m-%d %H:%i:%s') AS DATETIME)"
+			case Minute:
+				tmpl = "CAST(DATE_FORMAT($1, '%Y-%m-%d %H:%i:00') AS DATETIME)"
+			case Hour:
+				tmpl = "CAST(DATE_FORMAT($1, '%Y-%m-%d %H:00:00') AS DATETIME)"
+			case Day:
+				tmpl = "CAST(DATE_FORMAT($1, '%Y-%m-%d 00:00:00') AS DATETIME)"
+			case Week:
+				tmpl = "CAST(DATE_SUB(DATE($1), INTERVAL WEEKDAY($1) DAY) AS DATETIME)"
+			case Month:
+				tmpl = "CAST(DATE_FORMAT($1, '%Y-%m-01 00:00:00') AS DATETIME)"
+			case Quarter:
+				tmpl = "CAST(MAKEDATE(YEAR($1), 1) + INTERVAL QUARTER($1) - 1 QUARTER AS DATETIME)"
+			case Year:
+				tmpl = "CAST(DATE_FORMAT($1, '%Y-01-01 00:00:00') AS DATETIME)"
+			}
+		case dialect.DateTruncModifier:
+			switch unit {
+			case Second:
+				tmpl = "strftime('%Y-%m-%d %H:%M:%S', $1)"
+			case Minute:
+				tmpl = "strftime('%Y-%m-%d %H:%M:00', $1)"
+			case Hour:
+				tmpl = "strftime('%Y-%m-%d %H:00:00', $1)"
+			case Day:
+				tmpl = "datetime($1, 'start of day')"
+			case Week:
+				tmpl = "datetime($1, 'start of day', '-6 days', 'weekday 1')"
+			case Month:
+				tmpl = "datetime($1, 'start of month')"
+			case Quarter:
+				tmpl = "datetime($1, 'start of month', '-' || ((CAST(strftime('%m', $1) AS INTEGER) - 1) % 3) || ' months')"
+			case Year:
+				tmpl = "datetime($1, 'start of year')"
+			}
+		case dialect.DateTruncDateDiff:
+			switch unit {
+			case Second:
+				// DATEDIFF in seconds overflows with the 1900-01-01 epoch
+				tmpl = "DATEADD(second, DATEDIFF(second, '2000-01-01', $1), CAST('2000-01-01' AS DATETIME2))"
+			case Week:
+				// independent of @@DATEFIRST
+				tmpl = "CAST(DATEADD(day, -((DATEPART(weekday, $1) + @@DATEFIRST - 2) % 7), CAST($1 AS DATE)) AS DATETIME2)"
+			default:
+				tmpl = "DATEADD(" + unit.String() + ", DATEDIFF(" + unit.String() + ", 0, $1), 0)"
+			}
+		case dialect.DateTruncTrunc:
+			switch unit {
+			case Second:
+				tmpl = "CAST($1 AS DATE)"
+			case Minute:
+				tmpl = "TRUNC($1, 'MI')"
+			case Hour:
+				tmpl = "TRUNC($1, 'HH24')"
+			case Day:
+				tmpl = "TRUNC($1, 'DD')"
+			case Week:
+				tmpl = "TRUNC($1, 'IW')"
+			case Month:
+				tmpl = "TRUNC($1, 'MM')"
+			case Quarter:
+				tmpl = "TRUNC($1, 'Q')"
+			case Year:
+				tmpl = "TRUNC($1, 'YYYY')"
+			}
+		default:
+			return "", errUnsupported("Trunc", d)
+		}
+		if tmpl == "" {
+			return "", errUnsupportedUnit("Trunc", unit, d)
+		}
+		return sqlf.F(tmpl, arg(t)).BuildTo(ctx)
+	})
+}
+
+// Extract extracts the numeric part of unit from the time t, e.g.:
+//
+//	datetime.Extract(datetime.Year, t.Column("created_at"))
+//	// PostgreSQL: EXTRACT(YEAR FROM "t"."created_at")
+//	// SQLite:     CAST(strftime('%Y', "t"."created_at") AS INTEGER)
+//	// SQLServer:  DATEPART(year, [t].[created_at])
+//
+// The Week is the ISO week number, e.g. `WEEK(t, 3)` for MySQL. Week and
+// Quarter are not supported by AnsiSQL, whose EXTRACT has no such fields,
+// see dialect.DatePartStyle.
+func Extract(unit Unit, t any) sqlf.Builder {
+	return sqlf.Func(func(ctx sqlf.Context) (string, error) {
+		d := dialectOf(ctx)
+		if !unit.valid() {
+			return "", errUnsupportedUnit("Extract", unit, d)
+		}
+		var tmpl string
+		switch d.Capabilities().DatePart {
+		case dialect.DatePartExtractAll:
+			// the week field of PostgreSQL is the ISO week
+			tmpl = "EXTRACT(" + strings.ToUpper(unit.String()) + " FROM $1)"
+		case dialect.DatePartExtractWeekMode:
+			switch unit {
+			case Week:
+				// mode 3: weeks start on Monday, and week 1 has 4 or more days
+				tmpl = "WEEK($1, 3)"
+			default:
+				tmpl = "EXTRACT(" + strings.ToUpper(unit.String()) + " FROM $1)"
+			}
+		case dialect.DatePartStrftime:
+			switch unit {
+			case Second:
+				tmpl = "CAST(strftime('%S', $1) AS INTEGER)"
+			case Minute:
+				tmpl = "CAST(strftime('%M', $1) AS INTEGER)"
+			case Hour:
+				tmpl = "CAST(strftime('%H', $1) AS INTEGER)"
+			case Day:
+				tmpl = "CAST(strftime('%d', $1) AS INTEGER)"
+			case Week:
+				// day of year of the Thursday in the same ISO week,
+				// since strftime('%V') requires SQLite 3.46
+				tmpl = "((CAST(strftime('%j', $1, '-3 days', 'weekday 4') AS INTEGER) - 1) / 7 + 1)"
+			case Month:
+				tmpl = "CAST(strftime('%m', $1) AS INTEGER)"
+			case Quarter:
+				tmpl = "((CAST(strftime('%m', $1) AS INTEGER) + 2) / 3)"
+			case Year:
+				tmpl = "CAST(strftime('%Y', $1) AS INTEGER)"
+			}
+		case dialect.DatePartFunction:
+			switch unit {
+			case Week:
+				tmpl = "DATEPART(iso_week, $1)"
+			default:
+				tmpl = "DATEPART(" + unit.String() + ", $1)"
+			}
+		case dialect.DatePartExtractToChar:
+			switch unit {
+			case Week:
+				tmpl = "TO_NUMBER(TO_CHAR($1, 'IW'))"
+			case Quarter:
+				tmpl = "TO_NUMBER(TO_CHAR($1, 'Q'))"
+			default:
+				tmpl = "EXTRACT(" + strings.ToUpper(unit.String()) + " FROM $1)"
+			}
+		case dialect.DatePartExtract:
+			switch unit {
+			case Week, Quarter:
+			default:
+				tmpl = "EXTRACT(" + strings.ToUpper(unit.String()) + " FROM $1)"
+			}
+		default:
+			return "", errUnsupported("Extract", d)
+		}
+		if tmpl == "" {
+			return "", errUnsupportedUnit("Extract", unit, d)
+		}
+		return sqlf.F(tmpl, arg(t)).BuildTo(ctx)
+	})
+}
+
+// ConvertTimeZone converts the time t without time zone from the time zone
+// `from` to the local time of the time zone `to`, e.g.:
+//
+//	datetime.ConvertTimeZone(t.Column("created_at"), "UTC", "Asia/Shanghai")
+//	// PostgreSQL: (("t"."created_at" AT TIME ZONE $1) AT TIME ZONE $2)
+//	// MySQL:      CONVERT_TZ(`t`.`created_at`, ?, ?)
+//
+// Note that the time zone names must be recognized by the database,
+// e.g. SQL Server uses Windows time zone names like 'China Standard Time'.
+// It's not supported for SQLite, see dialect.TimeZoneConversionStyle.
+func ConvertTimeZone(t any, from, to string) sqlf.Builder {
+	return sqlf.Func(func(ctx sqlf.Context) (string, error) {
+		d := dialectOf(ctx)
+		switch d.Capabilities().TimeZoneConversion {
+		case dialect.TimeZoneConversionAtTimeZone:
+			return sqlf.F("((? AT TIME ZONE ?) AT TIME ZONE ?)", arg(t), from, to).BuildTo(ctx)
+		case dialect.TimeZoneConversionConvertTZ:
+			return sqlf.F("CONVERT_TZ(?, ?, ?)", arg(t), from, to).BuildTo(ctx)
+		case dialect.TimeZoneConversionFromTZ:
+			return sqlf.F("(FROM_TZ(CAST(? AS TIMESTAMP), ?) AT TIME ZONE ?)", arg(t), from, to).BuildTo(ctx)
+		default:
+			return "", errUnsupported("ConvertTimeZone", d)
+		}
+	})
+}
@@ -1,8 +1,9 @@
 package sqlb
 
 import (
+	"time"
+
 	"github.com/qjebbs/go-sqlf/v4"
-	"github.com/qjebbs/go-sqlf/v4/util"
 )
 
 var _ sqlf.Builder = (*DeleteBuilder)(nil)
@@ -12,14 +13,20 @@ var _ Builder = (*DeleteBuilder)(nil)
 // It's recommended to wrap it with your struct to provide a
 // more friendly API and improve fragment reusability.
 type DeleteBuilder struct {
-	target sqlf.Builder // target table to delete from.
-	where  *clauseList  // where conditions, joined with AND.
+	ctes      *clauseWith
+	target    sqlf.Builder   // target table to delete from.
+	where     *clauseList    // where conditions, joined with AND.
+	returning []sqlf.Builder // returning columns
+	timeout   time.Duration  // execution time limit
 	debugger
+
+	pruning bool
 }
 
 // NewDeleteBuilder returns a new DeleteBuilder.
 func NewDeleteBuilder() *DeleteBuilder {
 	return &DeleteBuilder{
+		ctes:  newWith(),
 		where: newPrefixedList("WHERE", " AND "),
 	}
 }
@@ -30,6 +37,39 @@ func (b *DeleteBuilder) DeleteFrom(table string) *DeleteBuilder {
 	return b
 }
 
+// Returning sets a RETURNING clause to the delete statement,
+// which is built as `OUTPUT DELETED.col` for SQLServer.
+func (b *DeleteBuilder) Returning(columns ...string) *DeleteBuilder {
+	b.returning = append(b.returning, returningColumns(columns)...)
+	return b
+}
+
+// With adds a builder as common table expression.
+//
+// The CTE will be automatically eliminated if all the conditions below are met:
+//   - Pruning is enabled by `b.EnableElimination()` or parent builders
+//   - The table is not referenced anywhere in the query
+//
+// The optional CTEOptions declares the column names and the materialization hint,
+// see SelectBuilder.With.
+func (b *DeleteBuilder) With(name Table, builder sqlf.Builder, opts ...CTEOptions) *DeleteBuilder {
+	b.ctes.With(name, builder, opts...)
+	return b
+}
+
+// WithRecursive adds a recursive common table expression, see SelectBuilder.WithRecursive.
+func (b *DeleteBuilder) WithRecursive(name Table, columns []string, anchor, recursive sqlf.Builder) *DeleteBuilder {
+	b.ctes.WithRecursive(name, columns, anchor, recursive)
+	return b
+}
+
+// WithValues adds a VALUES common table expression.
+// Supported dialects: Postgres, SQLite.
+func (b *DeleteBuilder) WithValues(name Table, columns, types []string, values [][]any) *DeleteBuilder {
+	b.ctes.WithValues(name, columns, types, values)
+	return b
+}
+
 // Where add a condition.  e.g.:
 //
 //	b.Where(sqlf.F(
@@ -115,23 +155,95 @@ func (b *DeleteBuilder) WhereNotBetween(column sqlf.Builder, start, end any) *De
 }
 
 // WhereIn adds a where IN condition like `t.id IN (1,2,3)`
+//
+// Lists longer than the threshold are rendered with the LargeInListStrategy
+// of the dialect, see ContextWithInListThreshold.
+// An empty list is built as an always-false predicate, see ContextWithStrictInList.
 func (b *DeleteBuilder) WhereIn(column sqlf.Builder, list any) *DeleteBuilder {
 	return b.Where(
-		sqlf.F(
-			"? IN (?)",
-			column,
-			sqlf.JoinArgs(util.FlattenArgs(list), ", "),
-		),
+		newInPredicate(column, list, false),
 	)
 }
 
 // WhereNotIn adds a where NOT IN condition like `t.id NOT IN (1,2,3)`
+//
+// Lists longer than the threshold are rendered with the LargeInListStrategy
+// of the dialect, see ContextWithInListThreshold.
+// An empty list is built as an always-true predicate, see ContextWithStrictInList.
 func (b *DeleteBuilder) WhereNotIn(column sqlf.Builder, list any) *DeleteBuilder {
 	return b.Where(
-		sqlf.F(
-			"? NOT IN (?)",
-			column,
-			sqlf.JoinArgs(util.FlattenArgs(list), ", "),
-		),
+		newInPredicate(column, list, true),
+	)
+}
+
+// WhereContains adds a LIKE condition like `t.name LIKE '%foo%' ESCAPE '!'`,
+// in which the wildcards in value are escaped to be matched literally.
+func (b *DeleteBuilder) WhereContains(column sqlf.Builder, value string) *DeleteBuilder {
+	return b.Where(
+		&likePredicate{column: column, value: value, prefix: true, suffix: true},
+	)
+}
+
+// WhereStartsWith adds a LIKE condition like `t.name LIKE 'foo%' ESCAPE '!'`,
+// in which the wildcards in value are escaped to be matched literally.
+func (b *DeleteBuilder) WhereStartsWith(column sqlf.Builder, value string) *DeleteBuilder {
+	return b.Where(
+		&likePredicate{column: column, value: value, suffix: true},
+	)
+}
+
+// WhereEndsWith adds a LIKE condition like `t.name LIKE '%foo' ESCAPE '!'`,
+// in which the wildcards in value are escaped to be matched literally.
+func (b *DeleteBuilder) WhereEndsWith(column sqlf.Builder, value string) *DeleteBuilder {
+	return b.Where(
+		&likePredicate{column: column, value: value, prefix: true},
+	)
+}
+
+// WhereContainsIgnoreCase is the case-insensitive version of WhereContains,
+// which is built as `t.name ILIKE ?` if supported by the dialect,
+// otherwise `LOWER(t.name) LIKE LOWER(?)`.
+func (b *DeleteBuilder) WhereContainsIgnoreCase(column sqlf.Builder, value string) *DeleteBuilder {
+	return b.Where(
+		&likePredicate{column: column, value: value, prefix: true, suffix: true, ignoreCase: true},
+	)
+}
+
+// WhereStartsWithIgnoreCase is the case-insensitive version of WhereStartsWith,
+// which is built as `t.name ILIKE ?` if supported by the dialect,
+// otherwise `LOWER(t.name) LIKE LOWER(?)`.
+func (b *DeleteBuilder) WhereStartsWithIgnoreCase(column sqlf.Builder, value string) *DeleteBuilder {
+	return b.Where(
+		&likePredicate{column: column, value: value, suffix: true, ignoreCase: true},
+	)
+}
+
+// WhereEndsWithIgnoreCase is the case-insensitive version of WhereEndsWith,
+// which is built as `t.name ILIKE ?` if supported by the dialect,
+// otherwise `LOWER(t.name) LIKE LOWER(?)`.
+func (b *DeleteBuilder) WhereEndsWithIgnoreCase(column sqlf.Builder, value string) *DeleteBuilder {
+	return b.Where(
+		&likePredicate{column: column, value: value, prefix: true, ignoreCase: true},
+	)
+}
+
+// WhereTupleIn adds a row value IN condition like `(t.a, t.b) IN ((1,2),(3,4))`,
+// which is emulated with OR-ed AND conditions on dialects without row values.
+//
+//	b.WhereTupleIn(t.Columns("a", "b"), [][]any{{1, 2}, {3, 4}})
+func (b *DeleteBuilder) WhereTupleIn(columns []sqlf.Builder, rows [][]any) *DeleteBuilder {
+	return b.Where(
+		&tupleInPredicate{columns: columns, rows: rows},
+	)
+}
+
+// WhereTupleCompare adds a row value comparison like `(t.a, t.b) > (1,2)`,
+// which is emulated with lexicographic OR-ed AND conditions on dialects without row values.
+// The op is one of "=", "<>", "<", "<=", ">", ">=".
+//
+//	b.WhereTupleCompare(t.Columns("created_at", "id"), ">", lastCreatedAt, lastID)
+func (b *DeleteBuilder) WhereTupleCompare(columns []sqlf.Builder, op string, values ...any) *DeleteBuilder {
+	return b.Where(
+		&tupleComparePredicate{columns: columns, op: op, values: values},
 	)
 }
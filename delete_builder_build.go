@@ -1,6 +1,7 @@
 package sqlb
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/qjebbs/go-sqlf/v4"
@@ -26,18 +27,69 @@ func (b *DeleteBuilder) Debug(name ...string) *DeleteBuilder {
 	return b
 }
 
+// EnableElimination enables CTE elimination based on dependency analysis.
+// To use elimination, make sure all table references are done via Table objects.
+func (b *DeleteBuilder) EnableElimination() *DeleteBuilder {
+	b.pruning = true
+	return b
+}
+
 // buildInternal builds the query with the selects.
 func (b *DeleteBuilder) buildInternal(ctx Context) (string, error) {
 	if b == nil {
 		return "", nil
 	}
+	caps := ctx.Dialect().Capabilities()
+	nested := isNestedStatement(ctx)
+	if !nested {
+		// the timeout applies to the top-level statement only
+		if err := checkTimeout(ctx, b.timeout); err != nil {
+			return "", err
+		}
+	}
+	ctx = contextWithNestedStatement(ctx)
+	ctx, pruning := decideContextPruning(ctx, b.pruning)
 	built := make([]string, 0)
+	if b.ctes.HasCTE() {
+		var required map[string]bool
+		if pruning {
+			myDeps, err := b.collectDependencies(ctx)
+			if err != nil {
+				return "", err
+			}
+			ctes, unresolved, err := b.ctes.CollectDependenciesForDeps(ctx, myDeps)
+			if err != nil {
+				return "", err
+			}
+			if deps := dependenciesFromContext(ctx); deps != nil {
+				deps.Merge(unresolved)
+				// collecting dependencies only,
+				// no need to build anything here
+				return "", nil
+			}
+			required = ctes
+		}
+		with, err := b.ctes.BuildRequired(ctx, required, nested)
+		if err != nil {
+			return "", err
+		}
+		if with != "" {
+			built = append(built, with)
+		}
+	}
 	// Delete target
-	r, err := sqlf.F("DELETE FROM ? ", b.target).BuildTo(ctx)
+	r, err := sqlf.F("DELETE FROM ?", b.target).BuildTo(ctx)
 	if err != nil {
 		return "", err
 	}
 	built = append(built, r)
+	if len(b.returning) > 0 && caps.SupportsOutputInserted {
+		output, err := buildOutput(ctx, "DELETED", b.returning)
+		if err != nil {
+			return "", err
+		}
+		built = append(built, output)
+	}
 	where, err := b.where.BuildTo(ctx)
 	if err != nil {
 		return "", err
@@ -45,7 +97,35 @@ func (b *DeleteBuilder) buildInternal(ctx Context) (string, error) {
 	if where != "" {
 		built = append(built, where)
 	}
+	if len(b.returning) > 0 {
+		switch {
+		case caps.SupportsReturning:
+			returning, err := sqlf.F("RETURNING ?", sqlf.Join(b.returning, ", ")).BuildTo(ctx)
+			if err != nil {
+				return "", fmt.Errorf("build returning clause: %w", err)
+			}
+			built = append(built, returning)
+		case caps.SupportsOutputInserted:
+			// already built
+		default:
+			return "", fmt.Errorf("returning is not supported for dialact %T", ctx.BaseDialect())
+		}
+	}
 	query := strings.TrimSpace(strings.Join(built, " "))
 	b.debugger.printIfDebug(ctx, query, ctx.Args())
 	return query, nil
 }
+
+// collectDependencies collects the dependencies of the tables.
+func (b *DeleteBuilder) collectDependencies(ctx Context) (*dependencies, error) {
+	myDeps := newDependencies(b.name)
+
+	// use a separate context to avoid polluting args
+	ctx = ContextWithNewArgStore(ctx)
+	depCtx := contextWithDependencies(ctx, myDeps)
+	_, err := sqlf.Join([]sqlf.Builder{b.target, b.where}, ";").BuildTo(depCtx)
+	if err != nil {
+		return nil, err
+	}
+	return myDeps, nil
+}
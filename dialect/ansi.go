@@ -27,19 +27,86 @@ func (AnsiSQL) Capabilities() Capabilities {
 
 		SupportsUpdateFrom: false,
 		SupportsUpdateJoin: false,
+
+		LargeInListStrategy:             InListExpand,
+		MaxInListSize:                   0,
+		ValuesRowConstructor:            "",
+		SupportsDerivedColumnList:       true,
+		SupportsBooleanLiteral:          true,
+		SupportsTupleIn:                 true,
+		SupportsTupleComparison:         true,
+		SupportsILike:                   false,
+		SupportsLikeCharClass:           false,
+		SupportsSetOperationAll:         true,
+		ExceptKeyword:                   "EXCEPT",
+		SupportsParenthesizedSetOperand: true,
+		RequiresRecursiveKeyword:        true,
+		RequiresRecursiveColumnList:     false,
+		SupportsCTEMaterialized:         false,
+		SupportsModifyingCTE:            false,
+		SupportsWindowClause:            true,
+		SupportsGroupsFrame:             true,
+		SupportsAggregateFilter:         true,
+		SupportsMultiColumnGrouping:     true,
+
+		Pagination: PaginationLimitOffset,
+
+		RowLocking:         RowLockingClause,
+		SupportsForShare:   false,
+		SupportsSkipLocked: false,
+		SupportsNoWait:     false,
+		SupportsLockOf:     false,
+
+		StatementHints:   StatementHintUnsupported,
+		IndexHints:       IndexHintUnsupported,
+		StatementTimeout: StatementTimeoutUnsupported,
+		Explain:          ExplainUnsupported,
+
+		LateralJoin:            LateralJoinLateral,
+		TableFuncColumns:       TableFuncColumnsAlias,
+		GroupingSets:           GroupingSetsStandard,
+		Concat:                 ConcatOperator,
+		Sequences:              SequenceNextValueFor,
+		LastInsertIDExpression: "",
+
+		LengthFunction:      "CHAR_LENGTH",
+		Substring:           SubstringFromFor,
+		CeilFunction:        "CEIL",
+		Mod:                 ModFunction,
+		RandomExpression:    "",
+		GreatestLeast:       GreatestLeastUnsupported,
+		NullReplaceFunction: "",
+
+		Interval:           IntervalStandard,
+		DateTrunc:          DateTruncUnsupported,
+		DatePart:           DatePartExtract,
+		TimeZoneConversion: TimeZoneConversionUnsupported,
+		JSON:               JSONUnsupported,
+		FullTextSearch:     FullTextSearchUnsupported,
 	}
 }
 
 // NullCoalesce provides a basic implementation for COALESCE.
 // It does not handle time.Time correctly and should be overridden by specific dialects.
 func (d AnsiSQL) NullCoalesce(column sqlf.Builder, goType reflect.Type) (sqlf.Builder, error) {
-	if !CheckNullCoalesceable(goType) {
+	if !needsCoalesce(goType) {
 		return nil, nil
 	}
+	if zero, ok := sqlZeroOf(goType); ok {
+		return sqlf.F("COALESCE(?, ?)", column, zero), nil
+	}
+	if goType == jsonRawMessageType {
+		// scan as JSON null, which can be unmarshaled safely
+		return sqlf.F("COALESCE(?, 'null')", column), nil
+	}
+	if isBinary(goType) {
+		return sqlf.F("COALESCE(?, X'')", column), nil
+	}
 	switch goType.Kind() {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
 		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
 		reflect.Float32, reflect.Float64:
+		// including time.Duration, which is scanned as nanoseconds
 		return sqlf.F("COALESCE(?, 0)", column), nil
 	case reflect.String:
 		return sqlf.F("COALESCE(?, '')", column), nil
@@ -2,6 +2,7 @@ package dialect
 
 import (
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"reflect"
 
@@ -59,9 +60,596 @@ type Capabilities struct {
 	// For example (PostgreSQL),
 	//   UPDATE foo SET val = bar.val FROM bar WHERE foo.id = bar.id
 	SupportsUpdateFrom bool
+
+	// LargeInListStrategy is the strategy to render IN lists that exceed the threshold,
+	// see sqlb.ContextWithInListThreshold.
+	LargeInListStrategy InListStrategy
+	// MaxInListSize is the maximum number of elements allowed in a single IN list,
+	// e.g. 1000 for Oracle, or 2000 for SQLServer to keep away from its limit of
+	// 2100 parameters per request. Zero means unlimited.
+	MaxInListSize int
+	// ValuesRowConstructor is the keyword prefixed to each row of a VALUES list,
+	// e.g. "ROW" for MySQL: VALUES ROW(1), ROW(2)
+	ValuesRowConstructor string
+	// SupportsDerivedColumnList indicates whether the dialect supports column
+	// alias list for derived tables.
+	//
+	// For example,
+	//   SELECT v FROM (VALUES (1), (2)) AS t (v)
+	SupportsDerivedColumnList bool
+	// SupportsBooleanLiteral indicates whether the dialect supports TRUE / FALSE
+	// literals as predicates, e.g. `WHERE FALSE`. If not, `1=1` / `1=0` are used.
+	SupportsBooleanLiteral bool
+	// SupportsTupleIn indicates whether the dialect supports row values with
+	// IN lists, otherwise it's emulated with OR-ed AND conditions.
+	//
+	// For example,
+	//   (a, b) IN ((1, 2), (3, 4))
+	SupportsTupleIn bool
+	// SupportsTupleComparison indicates whether the dialect supports comparing row values,
+	// otherwise it's emulated with OR-ed AND conditions.
+	//
+	// For example,
+	//   (a, b) > (1, 2)
+	SupportsTupleComparison bool
+	// SupportsILike indicates whether the dialect supports ILIKE operator for
+	// case-insensitive matching, otherwise `LOWER(col) LIKE LOWER(?)` is used.
+	SupportsILike bool
+	// SupportsLikeCharClass indicates whether LIKE patterns support `[...]` character
+	// classes, e.g. SQL Server, in which `[` must be escaped to be matched literally.
+	SupportsLikeCharClass bool
+	// SupportsSetOperationAll indicates whether the dialect supports the ALL
+	// variants of INTERSECT and EXCEPT, e.g. `INTERSECT ALL`.
+	SupportsSetOperationAll bool
+	// ExceptKeyword is the keyword of the EXCEPT set operation, e.g. "MINUS"
+	// for Oracle. Empty means EXCEPT is not supported.
+	ExceptKeyword string
+	// SupportsParenthesizedSetOperand indicates whether the operands of set
+	// operations can be parenthesized, e.g. `(SELECT ... LIMIT 1) UNION ...`,
+	// otherwise they are wrapped as `SELECT * FROM (SELECT ... LIMIT 1) UNION ...`.
+	SupportsParenthesizedSetOperand bool
+	// RequiresRecursiveKeyword indicates whether recursive CTEs require the
+	// RECURSIVE keyword, e.g. `WITH RECURSIVE t AS (...)`.
+	RequiresRecursiveKeyword bool
+	// RequiresRecursiveColumnList indicates whether recursive CTEs require the
+	// column list, e.g. `WITH t (id, parent_id) AS (...)` for Oracle.
+	RequiresRecursiveColumnList bool
+	// SupportsCTEMaterialized indicates whether the dialect supports the
+	// materialization hints of CTEs, e.g. `t AS MATERIALIZED (...)`.
+	SupportsCTEMaterialized bool
+	// SupportsModifyingCTE indicates whether the dialect supports data-modifying
+	// statements in CTEs, e.g. `WITH t AS (DELETE ... RETURNING *) ...`.
+	SupportsModifyingCTE bool
+	// SupportsWindowClause indicates whether the dialect supports the named
+	// windows, e.g. `SELECT SUM(x) OVER w FROM t WINDOW w AS (...)`.
+	SupportsWindowClause bool
+	// SupportsGroupsFrame indicates whether the dialect supports the GROUPS
+	// frame unit of windows, e.g. `GROUPS BETWEEN 1 PRECEDING AND CURRENT ROW`.
+	SupportsGroupsFrame bool
+	// SupportsAggregateFilter indicates whether the dialect supports the FILTER
+	// clause of aggregates, e.g. `COUNT(*) FILTER (WHERE ...)`, otherwise it's
+	// emulated with CASE expressions.
+	SupportsAggregateFilter bool
+	// SupportsMultiColumnGrouping indicates whether the GROUPING function accepts
+	// multiple columns, e.g. `GROUPING(a, b)`, otherwise `GROUPING_ID(a, b)` is used.
+	SupportsMultiColumnGrouping bool
+
+	// Pagination is how the dialect limits the rows of queries, see PaginationStyle.
+	Pagination PaginationStyle
+
+	// RowLocking is how the dialect locks the selected rows, see RowLockingStyle.
+	RowLocking RowLockingStyle
+	// SupportsForShare indicates whether the dialect supports shared row locks,
+	// e.g. `FOR SHARE`.
+	SupportsForShare bool
+	// SupportsSkipLocked indicates whether the dialect supports skipping the locked rows,
+	// e.g. `FOR UPDATE SKIP LOCKED`.
+	SupportsSkipLocked bool
+	// SupportsNoWait indicates whether the dialect supports failing immediately
+	// on the locked rows, e.g. `FOR UPDATE NOWAIT`.
+	SupportsNoWait bool
+	// SupportsLockOf indicates whether the dialect supports locking the rows of
+	// the specified tables only, e.g. `FOR UPDATE OF t`.
+	SupportsLockOf bool
+
+	// StatementHints is where the dialect places the statement level
+	// optimizer hints, see StatementHintStyle.
+	StatementHints StatementHintStyle
+	// IndexHints is how the dialect hints the indexes of tables, see IndexHintStyle.
+	IndexHints IndexHintStyle
+	// StatementTimeout is how the dialect limits the execution time of
+	// a statement, see StatementTimeoutStyle.
+	StatementTimeout StatementTimeoutStyle
+	// Explain is how the dialect explains the plans of statements, see ExplainStyle.
+	Explain ExplainStyle
+
+	// LateralJoin is how the dialect joins the subqueries referencing
+	// the preceding tables, see LateralJoinStyle.
+	LateralJoin LateralJoinStyle
+	// TableFuncColumns is where the dialect places the column definitions
+	// of table-valued functions, see TableFuncColumnStyle.
+	TableFuncColumns TableFuncColumnStyle
+	// GroupingSets is how the dialect groups rows by multiple grouping sets,
+	// e.g. ROLLUP, CUBE and GROUPING SETS, see GroupingSetsStyle.
+	GroupingSets GroupingSetsStyle
+	// Concat is how the dialect concatenates strings, see ConcatStyle.
+	Concat ConcatStyle
+	// Sequences is how the dialect gets the next value of sequences, see SequenceStyle.
+	Sequences SequenceStyle
+	// LastInsertIDExpression is the expression returning the last value generated
+	// by the identity / auto-increment column in the session, e.g. LAST_INSERT_ID(),
+	// which is empty if not supported.
+	LastInsertIDExpression string
+
+	// LengthFunction is the function counting the characters of strings,
+	// e.g. CHAR_LENGTH, LENGTH or LEN, which is empty if not supported.
+	LengthFunction string
+	// Substring is how the dialect extracts substrings, see SubstringStyle.
+	Substring SubstringStyle
+	// CeilFunction is the function rounding numbers up, e.g. CEIL or CEILING,
+	// which is empty if not supported.
+	CeilFunction string
+	// Mod is how the dialect gets the remainder of divisions, see ModStyle.
+	Mod ModStyle
+	// RandomExpression is the expression returning a random value,
+	// e.g. RANDOM() or NEWID(), which is empty if not supported.
+	RandomExpression string
+	// GreatestLeast is how the dialect gets the largest and the smallest
+	// of the arguments, see GreatestLeastStyle.
+	GreatestLeast GreatestLeastStyle
+	// NullReplaceFunction is the function preferred over COALESCE of two
+	// arguments, e.g. NVL of Oracle, which is empty if there is none.
+	NullReplaceFunction string
+
+	// Interval is how the dialect adds intervals to date / time values, see IntervalStyle.
+	Interval IntervalStyle
+	// DateTrunc is how the dialect truncates date / time values to the start
+	// of calendar units, see DateTruncStyle.
+	DateTrunc DateTruncStyle
+	// DatePart is how the dialect extracts the parts of date / time values,
+	// see DatePartStyle.
+	DatePart DatePartStyle
+	// TimeZoneConversion is how the dialect converts times between time zones,
+	// see TimeZoneConversionStyle.
+	TimeZoneConversion TimeZoneConversionStyle
+	// JSON is how the dialect accesses the paths of JSON documents, see JSONStyle.
+	JSON JSONStyle
+	// FullTextSearch is how the dialect searches the full-text indexes,
+	// see FullTextSearchStyle.
+	FullTextSearch FullTextSearchStyle
+}
+
+// InListStrategy is the strategy to render large IN lists.
+type InListStrategy int
+
+const (
+	// InListExpand expands every element into its own placeholder, e.g.:
+	//   col IN (?, ?, ?)
+	InListExpand InListStrategy = iota
+	// InListArray binds the whole list as a single array, e.g.:
+	//   col = ANY($1)
+	InListArray
+	// InListValues matches against a VALUES list, in which integers are inlined
+	// as literals to keep away from the bind parameter limit, e.g.:
+	//   col IN (SELECT v FROM (VALUES (1), (2)) AS t (v))
+	InListValues
+	// InListChunked splits the list into OR-ed IN chunks of MaxInListSize, e.g.:
+	//   (col IN (:1, :2) OR col IN (:3, :4))
+	InListChunked
+	// InListJSON binds the whole list as a single JSON array, which is expanded
+	// by the table-valued function of the dialect, e.g.:
+	//   col IN (SELECT value FROM OPENJSON(@p1))
+	InListJSON
+)
+
+// PaginationStyle is the style of limiting the rows of queries.
+type PaginationStyle int
+
+const (
+	// PaginationLimitOffset limits the rows with LIMIT / OFFSET, e.g.:
+	//   SELECT ... LIMIT 10 OFFSET 20
+	PaginationLimitOffset PaginationStyle = iota
+	// PaginationTop limits the rows with TOP, and pages them with OFFSET / FETCH,
+	// which requires ORDER BY, e.g.:
+	//   SELECT TOP 10 ...
+	//   SELECT ... ORDER BY id OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY
+	PaginationTop
+	// PaginationOffsetFetch limits the rows with OFFSET / FETCH, e.g.:
+	//   SELECT ... OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY
+	PaginationOffsetFetch
+)
+
+// RowLockingStyle is the style of row locking in SELECT statements.
+type RowLockingStyle int
+
+const (
+	// RowLockingUnsupported indicates the dialect cannot lock the selected rows.
+	RowLockingUnsupported RowLockingStyle = iota
+	// RowLockingClause locks rows with the trailing locking clause, e.g.:
+	//   SELECT ... FOR UPDATE SKIP LOCKED
+	RowLockingClause
+	// RowLockingTableHints locks rows with table hints, e.g.:
+	//   SELECT ... FROM t WITH (UPDLOCK, READPAST, ROWLOCK)
+	RowLockingTableHints
+)
+
+// StatementHintStyle is the placement of statement level optimizer hints.
+type StatementHintStyle int
+
+const (
+	// StatementHintUnsupported indicates the dialect has no optimizer hints,
+	// and the hints are dropped.
+	StatementHintUnsupported StatementHintStyle = iota
+	// StatementHintComment places hints in a comment after SELECT, e.g.:
+	//   SELECT /*+ PARALLEL(4) */ ...
+	StatementHintComment
+	// StatementHintLeadingComment places hints in a comment before the
+	// statement, e.g. pg_hint_plan of PostgreSQL:
+	//   /*+ SeqScan(t) */ SELECT ...
+	StatementHintLeadingComment
+	// StatementHintOption places hints in the trailing OPTION clause, e.g.:
+	//   SELECT ... OPTION (RECOMPILE)
+	StatementHintOption
+)
+
+// IndexHintStyle is the style of index hints of tables.
+type IndexHintStyle int
+
+const (
+	// IndexHintUnsupported indicates the dialect has no index hints,
+	// and the hints are dropped.
+	IndexHintUnsupported IndexHintStyle = iota
+	// IndexHintClause hints indexes after the table, e.g.:
+	//   FROM t USE INDEX (idx)
+	IndexHintClause
+	// IndexHintTableHint hints indexes with table hints, e.g.:
+	//   FROM t WITH (INDEX(idx))
+	IndexHintTableHint
+	// IndexHintComment hints indexes in the statement hint comment, e.g.:
+	//   SELECT /*+ INDEX(t idx) */ ...
+	IndexHintComment
+	// IndexHintIndexedBy forces a single index after the table, e.g.:
+	//   FROM t INDEXED BY idx
+	IndexHintIndexedBy
+)
+
+// StatementTimeoutStyle is the style of limiting the execution time of a statement.
+type StatementTimeoutStyle int
+
+const (
+	// StatementTimeoutUnsupported indicates the dialect cannot limit the
+	// execution time at the SQL level, e.g. SQLServer, which has no query
+	// option for it and whose SET LOCK_TIMEOUT limits the lock waits only,
+	// and Oracle, which has no such hint and whose Resource Manager limits
+	// are configured by the DBA for consumer groups rather than per statement.
+	StatementTimeoutUnsupported StatementTimeoutStyle = iota
+	// StatementTimeoutHint limits the execution time with the optimizer hint, e.g.:
+	//   SELECT /*+ MAX_EXECUTION_TIME(1000) */ ...
+	StatementTimeoutHint
+	// StatementTimeoutSetLocal limits the execution time with a preceding
+	// statement in the same transaction, e.g.:
+	//   SET LOCAL statement_timeout = 1000
+	StatementTimeoutSetLocal
+)
+
+// ExplainStyle is the style of explaining the plans of statements.
+type ExplainStyle int
+
+const (
+	// ExplainUnsupported indicates the dialect cannot explain statements.
+	ExplainUnsupported ExplainStyle = iota
+	// ExplainOptionList explains with the parenthesized option list, e.g.:
+	//   EXPLAIN (ANALYZE, FORMAT JSON) SELECT ...
+	ExplainOptionList
+	// ExplainFormatOption explains with the FORMAT option or ANALYZE, e.g.:
+	//   EXPLAIN FORMAT=JSON SELECT ...
+	ExplainFormatOption
+	// ExplainQueryPlan explains with EXPLAIN QUERY PLAN, e.g.:
+	//   EXPLAIN QUERY PLAN SELECT ...
+	ExplainQueryPlan
+	// ExplainShowPlan explains by turning on the plan output of the session, e.g.:
+	//   SET SHOWPLAN_XML ON; SELECT ...; SET SHOWPLAN_XML OFF
+	ExplainShowPlan
+	// ExplainPlanFor writes the plan to the plan table and reads it back, e.g.:
+	//   EXPLAIN PLAN FOR SELECT ...; SELECT PLAN_TABLE_OUTPUT FROM TABLE(DBMS_XPLAN.DISPLAY())
+	ExplainPlanFor
+)
+
+// LateralJoinStyle is the style of joining the correlated subqueries.
+type LateralJoinStyle int
+
+const (
+	// LateralJoinUnsupported indicates the dialect cannot join correlated subqueries.
+	LateralJoinUnsupported LateralJoinStyle = iota
+	// LateralJoinLateral joins with the LATERAL keyword, e.g.:
+	//   CROSS JOIN LATERAL (SELECT ...) AS s
+	//   LEFT JOIN LATERAL (SELECT ...) AS s ON TRUE
+	LateralJoinLateral
+	// LateralJoinApply joins with the APPLY operators, e.g.:
+	//   CROSS APPLY (SELECT ...) AS s
+	//   OUTER APPLY (SELECT ...) AS s
+	LateralJoinApply
+)
+
+// TableFuncColumnStyle is the placement of the column definitions of table-valued functions.
+type TableFuncColumnStyle int
+
+const (
+	// TableFuncColumnsUnsupported indicates the dialect cannot define the
+	// columns of table-valued functions, e.g. json_each of SQLite.
+	TableFuncColumnsUnsupported TableFuncColumnStyle = iota
+	// TableFuncColumnsAlias defines the columns after the alias, e.g.:
+	//   generate_series(1, 10) AS g (n)
+	TableFuncColumnsAlias
+	// TableFuncColumnsClause defines the columns in the COLUMNS clause
+	// inside the function call, e.g.:
+	//   JSON_TABLE(?, '$[*]' COLUMNS (id INT PATH '$.id')) AS j
+	TableFuncColumnsClause
+	// TableFuncColumnsWith defines the columns in the WITH clause
+	// after the function call, e.g.:
+	//   OPENJSON(@p1) WITH (id INT '$.id') AS j
+	TableFuncColumnsWith
+)
+
+// GroupingSetsStyle is the style of grouping rows by multiple grouping sets.
+type GroupingSetsStyle int
+
+const (
+	// GroupingSetsUnsupported indicates the dialect cannot group by multiple grouping sets.
+	GroupingSetsUnsupported GroupingSetsStyle = iota
+	// GroupingSetsStandard supports ROLLUP, CUBE and GROUPING SETS, e.g.:
+	//   GROUP BY ROLLUP(a, b)
+	//   GROUP BY GROUPING SETS ((a, b), (a), ())
+	GroupingSetsStandard
+	// GroupingSetsWithRollup supports the rollup of the whole GROUP BY list only, e.g.:
+	//   GROUP BY a, b WITH ROLLUP
+	GroupingSetsWithRollup
+)
+
+// ConcatStyle is the style of concatenating strings.
+type ConcatStyle int
+
+const (
+	// ConcatUnsupported indicates the dialect cannot concatenate strings.
+	ConcatUnsupported ConcatStyle = iota
+	// ConcatOperator concatenates with the standard operator, e.g.:
+	//   (a || b)
+	ConcatOperator
+	// ConcatFunction concatenates with the CONCAT function, e.g.:
+	//   CONCAT(a, b)
+	ConcatFunction
+	// ConcatPlus concatenates with the + operator, e.g.:
+	//   (a + b)
+	ConcatPlus
+)
+
+// SequenceStyle is the style of getting the next value of a sequence.
+type SequenceStyle int
+
+const (
+	// SequenceUnsupported indicates the dialect has no sequences.
+	SequenceUnsupported SequenceStyle = iota
+	// SequenceNextValFunction passes the sequence name to the nextval function, e.g.:
+	//   nextval('"public"."seq"')
+	SequenceNextValFunction
+	// SequenceNextValueFor gets the next value with the standard expression, e.g.:
+	//   NEXT VALUE FOR [dbo].[seq]
+	SequenceNextValueFor
+	// SequencePseudoColumn gets the next value with the NEXTVAL pseudo column, e.g.:
+	//   "seq".NEXTVAL
+	SequencePseudoColumn
+)
+
+// SubstringStyle is the style of extracting substrings.
+type SubstringStyle int
+
+const (
+	// SubstringUnsupported indicates the dialect cannot extract substrings.
+	SubstringUnsupported SubstringStyle = iota
+	// SubstringFromFor extracts with the standard syntax, e.g.:
+	//   SUBSTRING(s FROM 1 FOR 3)
+	SubstringFromFor
+	// SubstringFunction extracts with the SUBSTRING function, e.g.:
+	//   SUBSTRING(s, 1, 3)
+	SubstringFunction
+	// SubstringSubstr extracts with the SUBSTR function, e.g.:
+	//   SUBSTR(s, 1, 3)
+	SubstringSubstr
+)
+
+// ModStyle is the style of getting the remainder of divisions.
+type ModStyle int
+
+const (
+	// ModUnsupported indicates the dialect cannot get the remainder of divisions.
+	ModUnsupported ModStyle = iota
+	// ModFunction gets the remainder with the MOD function, e.g.:
+	//   MOD(x, y)
+	ModFunction
+	// ModOperator gets the remainder with the % operator, e.g.:
+	//   (x % y)
+	ModOperator
+)
+
+// GreatestLeastStyle is the style of getting the largest and the smallest of the arguments.
+type GreatestLeastStyle int
+
+const (
+	// GreatestLeastUnsupported indicates the dialect has no such functions.
+	GreatestLeastUnsupported GreatestLeastStyle = iota
+	// GreatestLeastFunction uses the GREATEST and LEAST functions, e.g.:
+	//   GREATEST(a, b)
+	GreatestLeastFunction
+	// GreatestLeastMinMax uses the multi-argument MAX and MIN functions, e.g.:
+	//   MAX(a, b)
+	GreatestLeastMinMax
+)
+
+// IntervalStyle is the style of adding intervals to date / time values.
+type IntervalStyle int
+
+const (
+	// IntervalUnsupported indicates the dialect cannot add intervals.
+	IntervalUnsupported IntervalStyle = iota
+	// IntervalStandard adds the standard interval literals, e.g.:
+	//   (t + INTERVAL '1' DAY)
+	IntervalStandard
+	// IntervalString adds the interval literals with the unit inside the string, e.g.:
+	//   (t + INTERVAL '1 day')
+	IntervalString
+	// IntervalDateAdd adds intervals with the DATE_ADD function, e.g.:
+	//   DATE_ADD(t, INTERVAL 1 DAY)
+	IntervalDateAdd
+	// IntervalDatePart adds the number of date parts with the DATEADD function, e.g.:
+	//   DATEADD(day, 1, t)
+	IntervalDatePart
+	// IntervalModifier adds intervals with the modifiers of the datetime function, e.g.:
+	//   datetime(t, '+1 days')
+	IntervalModifier
+	// IntervalNumToDS adds the day-to-second intervals, and months with
+	// the ADD_MONTHS function, e.g.:
+	//   (t + NUMTODSINTERVAL(1, 'DAY'))
+	//   ADD_MONTHS(t, 1)
+	IntervalNumToDS
+)
+
+// DateTruncStyle is the style of truncating date / time values to the start of calendar units.
+type DateTruncStyle int
+
+const (
+	// DateTruncUnsupported indicates the dialect cannot truncate date / time values.
+	DateTruncUnsupported DateTruncStyle = iota
+	// DateTruncFunction truncates with the DATE_TRUNC function, e.g.:
+	//   DATE_TRUNC('day', t)
+	DateTruncFunction
+	// DateTruncFormat truncates by formatting the values, e.g.:
+	//   CAST(DATE_FORMAT(t, '%Y-%m-%d 00:00:00') AS DATETIME)
+	DateTruncFormat
+	// DateTruncModifier truncates with the modifiers of the datetime function, e.g.:
+	//   datetime(t, 'start of day')
+	DateTruncModifier
+	// DateTruncDateDiff truncates by adding the date parts elapsed since the epoch, e.g.:
+	//   DATEADD(day, DATEDIFF(day, 0, t), 0)
+	DateTruncDateDiff
+	// DateTruncTrunc truncates with the TRUNC function and format models, e.g.:
+	//   TRUNC(t, 'DD')
+	DateTruncTrunc
+)
+
+// DatePartStyle is the style of extracting the parts of date / time values.
+type DatePartStyle int
+
+const (
+	// DatePartUnsupported indicates the dialect cannot extract date parts.
+	DatePartUnsupported DatePartStyle = iota
+	// DatePartExtract extracts the standard fields with EXTRACT, which has
+	// no week and quarter, e.g.:
+	//   EXTRACT(YEAR FROM t)
+	DatePartExtract
+	// DatePartExtractAll extracts all the fields with EXTRACT, in which
+	// the week is the ISO week, e.g.:
+	//   EXTRACT(WEEK FROM t)
+	DatePartExtractAll
+	// DatePartExtractWeekMode extracts with EXTRACT, and the ISO week
+	// with the WEEK function, e.g.:
+	//   WEEK(t, 3)
+	DatePartExtractWeekMode
+	// DatePartExtractToChar extracts with EXTRACT, and the week and quarter
+	// by formatting the values, e.g.:
+	//   TO_NUMBER(TO_CHAR(t, 'IW'))
+	DatePartExtractToChar
+	// DatePartStrftime extracts by formatting the values with strftime, e.g.:
+	//   CAST(strftime('%Y', t) AS INTEGER)
+	DatePartStrftime
+	// DatePartFunction extracts with the DATEPART function, e.g.:
+	//   DATEPART(year, t)
+	DatePartFunction
+)
+
+// TimeZoneConversionStyle is the style of converting times between time zones.
+type TimeZoneConversionStyle int
+
+const (
+	// TimeZoneConversionUnsupported indicates the dialect cannot convert time zones.
+	TimeZoneConversionUnsupported TimeZoneConversionStyle = iota
+	// TimeZoneConversionAtTimeZone converts with the AT TIME ZONE operator, e.g.:
+	//   ((t AT TIME ZONE 'UTC') AT TIME ZONE 'Asia/Shanghai')
+	TimeZoneConversionAtTimeZone
+	// TimeZoneConversionConvertTZ converts with the CONVERT_TZ function, e.g.:
+	//   CONVERT_TZ(t, 'UTC', 'Asia/Shanghai')
+	TimeZoneConversionConvertTZ
+	// TimeZoneConversionFromTZ converts with the FROM_TZ function, e.g.:
+	//   (FROM_TZ(CAST(t AS TIMESTAMP), 'UTC') AT TIME ZONE 'Asia/Shanghai')
+	TimeZoneConversionFromTZ
+)
+
+// JSONStyle is the style of accessing the paths of JSON documents.
+type JSONStyle int
+
+const (
+	// JSONUnsupported indicates the dialect cannot access JSON documents.
+	JSONUnsupported JSONStyle = iota
+	// JSONOperators accesses with the jsonb operators, e.g.:
+	//   (doc -> 'a' ->> 0)
+	//   doc @> '{"a":1}'
+	JSONOperators
+	// JSONFunctions accesses with the JSON functions and SQL/JSON paths, e.g.:
+	//   JSON_UNQUOTE(JSON_EXTRACT(doc, '$.a[0]'))
+	//   JSON_CONTAINS(doc, '{"a":1}')
+	JSONFunctions
+	// JSON1 accesses with the functions of the SQLite JSON1 extension, e.g.:
+	//   json_extract(doc, '$.a[0]')
+	JSON1
+	// JSONOpenJSON accesses with JSON_VALUE, JSON_QUERY and OPENJSON, e.g.:
+	//   JSON_VALUE(doc, '$.a[0]')
+	JSONOpenJSON
+)
+
+// FullTextSearchStyle is the style of searching the full-text indexes.
+type FullTextSearchStyle int
+
+const (
+	// FullTextSearchUnsupported indicates the dialect has no full-text search.
+	FullTextSearchUnsupported FullTextSearchStyle = iota
+	// FullTextSearchTSVector matches the text search vectors, e.g.:
+	//   to_tsvector(col) @@ plainto_tsquery('foo')
+	FullTextSearchTSVector
+	// FullTextSearchMatchAgainst matches with MATCH ... AGAINST, e.g.:
+	//   MATCH (col) AGAINST ('foo' IN NATURAL LANGUAGE MODE)
+	FullTextSearchMatchAgainst
+	// FullTextSearchContains matches with the FREETEXT and CONTAINS predicates, e.g.:
+	//   FREETEXT(col, 'foo')
+	FullTextSearchContains
+	// FullTextSearchFTS5 matches the FTS5 virtual tables, e.g.:
+	//   col MATCH 'foo'
+	FullTextSearchFTS5
+)
+
+// SQLZeroer is implemented by the types whose zero value in the database
+// differs from the zero value of their kind, e.g. decimal and UUID types
+// based on string, so that NullCoalesce can coalesce NULLs to a valid value.
+//
+// The types based on string are coalesced to the empty string otherwise,
+// which is rejected by the columns of non-textual types, e.g. uuid and
+// numeric of PostgreSQL, and uniqueidentifier and decimal of SQLServer:
+//
+//	type Decimal string
+//
+//	func (Decimal) SQLZero() any { return "0" }
+//
+//	type UUID string
+//
+//	func (UUID) SQLZero() any { return "00000000-0000-0000-0000-000000000000" }
+type SQLZeroer interface {
+	SQLZero() any
 }
 
-var scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+var (
+	scannerType        = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	sqlZeroerType      = reflect.TypeOf((*SQLZeroer)(nil)).Elem()
+	bytesType          = reflect.TypeOf([]byte(nil))
+	jsonRawMessageType = reflect.TypeOf(json.RawMessage(nil))
+)
 
 // CheckNullCoalesceable checks if a type is a candidate for NullCoalesce.
 // It returns false if the type is a pointer or implements sql.Scanner,
@@ -76,6 +664,31 @@ func CheckNullCoalesceable(goType reflect.Type) bool {
 	return true
 }
 
+// needsCoalesce reports whether the built-in dialects coalesce the type,
+// which excludes []byte in addition to CheckNullCoalesceable, since
+// database/sql scans NULL into []byte as nil.
+func needsCoalesce(goType reflect.Type) bool {
+	return CheckNullCoalesceable(goType) && goType != bytesType
+}
+
+// sqlZeroOf returns the value provided by SQLZeroer if the type implements it.
+func sqlZeroOf(goType reflect.Type) (any, bool) {
+	if !goType.Implements(sqlZeroerType) {
+		return nil, false
+	}
+	return reflect.Zero(goType).Interface().(SQLZeroer).SQLZero(), true
+}
+
+// isBinary reports whether the type is a byte slice to be coalesced
+// to empty binary, e.g. `type Blob []byte`, excluding json.RawMessage
+// and SQLZeroer implementations.
+func isBinary(goType reflect.Type) bool {
+	if goType == jsonRawMessageType || goType.Implements(sqlZeroerType) {
+		return false
+	}
+	return goType.Kind() == reflect.Slice && goType.Elem().Kind() == reflect.Uint8
+}
+
 // Upgrade attempts to upgrade a sqlf/dialect.Dialect to a sqlb/dialect.Dialect.
 func Upgrade(d dialect.Dialect) (Dialect, bool) {
 	if dialect, ok := d.(Dialect); ok {
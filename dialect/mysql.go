@@ -28,12 +28,68 @@ func (MySQL) Capabilities() Capabilities {
 
 		SupportsUpdateFrom: false,
 		SupportsUpdateJoin: true,
+
+		LargeInListStrategy:             InListValues,
+		MaxInListSize:                   0,
+		ValuesRowConstructor:            "ROW",
+		SupportsDerivedColumnList:       true,
+		SupportsBooleanLiteral:          true,
+		SupportsTupleIn:                 true,
+		SupportsTupleComparison:         true,
+		SupportsILike:                   false,
+		SupportsLikeCharClass:           false,
+		SupportsSetOperationAll:         true,
+		ExceptKeyword:                   "EXCEPT",
+		SupportsParenthesizedSetOperand: true,
+		RequiresRecursiveKeyword:        true,
+		RequiresRecursiveColumnList:     false,
+		SupportsCTEMaterialized:         false,
+		SupportsModifyingCTE:            false,
+		SupportsWindowClause:            true,
+		SupportsGroupsFrame:             false,
+		SupportsAggregateFilter:         false,
+		SupportsMultiColumnGrouping:     true,
+
+		Pagination: PaginationLimitOffset,
+
+		RowLocking:         RowLockingClause,
+		SupportsForShare:   true,
+		SupportsSkipLocked: true,
+		SupportsNoWait:     true,
+		SupportsLockOf:     true,
+
+		StatementHints:   StatementHintComment,
+		IndexHints:       IndexHintClause,
+		StatementTimeout: StatementTimeoutHint,
+		Explain:          ExplainFormatOption,
+
+		LateralJoin:            LateralJoinLateral,
+		TableFuncColumns:       TableFuncColumnsClause,
+		GroupingSets:           GroupingSetsWithRollup,
+		Concat:                 ConcatFunction,
+		Sequences:              SequenceUnsupported,
+		LastInsertIDExpression: "LAST_INSERT_ID()",
+
+		LengthFunction:      "CHAR_LENGTH",
+		Substring:           SubstringFunction,
+		CeilFunction:        "CEIL",
+		Mod:                 ModFunction,
+		RandomExpression:    "RAND()",
+		GreatestLeast:       GreatestLeastFunction,
+		NullReplaceFunction: "",
+
+		Interval:           IntervalDateAdd,
+		DateTrunc:          DateTruncFormat,
+		DatePart:           DatePartExtractWeekMode,
+		TimeZoneConversion: TimeZoneConversionConvertTZ,
+		JSON:               JSONFunctions,
+		FullTextSearch:     FullTextSearchMatchAgainst,
 	}
 }
 
 // NullCoalesce provides a MySQL specific implementation for COALESCE, especially for time.Time type.
 func (d MySQL) NullCoalesce(column sqlf.Builder, goType reflect.Type) (sqlf.Builder, error) {
-	if !CheckNullCoalesceable(goType) {
+	if !needsCoalesce(goType) {
 		return nil, nil
 	}
 	// Use AssignableTo to handle custom type aliases.
@@ -1,6 +1,7 @@
 package dialect
 
 import (
+	"fmt"
 	"reflect"
 	"time"
 
@@ -25,6 +26,16 @@ type Oracle struct {
 	// the column and the configured FALSE value that is
 	// returned for NULLs.
 	BoolFalseValue any
+
+	// EmptyStringValue optionally specifies the sentinel value used to
+	// represent the empty string in NVL/COALESCE expressions, e.g., " " or "-",
+	// defaulting to '' if not set.
+	//
+	// Since Oracle treats the empty string as NULL, `NVL(col, '')` still
+	// returns NULL, which is fine for the drivers scanning NULL into
+	// an empty string. Otherwise, set it and map the sentinel back to
+	// the empty string when scanning.
+	EmptyStringValue any
 }
 
 // OracleBoolKind defines the underlying type for boolean representation in Oracle.
@@ -52,22 +63,91 @@ func (Oracle) Capabilities() Capabilities {
 
 		SupportsUpdateFrom: false,
 		SupportsUpdateJoin: false,
+
+		LargeInListStrategy:             InListChunked,
+		MaxInListSize:                   1000,
+		ValuesRowConstructor:            "",
+		SupportsDerivedColumnList:       false,
+		SupportsBooleanLiteral:          false,
+		SupportsTupleIn:                 true,
+		SupportsTupleComparison:         false,
+		SupportsILike:                   false,
+		SupportsLikeCharClass:           false,
+		SupportsSetOperationAll:         false,
+		ExceptKeyword:                   "MINUS",
+		SupportsParenthesizedSetOperand: true,
+		RequiresRecursiveKeyword:        false,
+		RequiresRecursiveColumnList:     true,
+		SupportsCTEMaterialized:         false,
+		SupportsModifyingCTE:            false,
+		SupportsWindowClause:            false,
+		SupportsGroupsFrame:             false,
+		SupportsAggregateFilter:         false,
+		SupportsMultiColumnGrouping:     false,
+
+		Pagination: PaginationOffsetFetch,
+
+		RowLocking:         RowLockingClause,
+		SupportsForShare:   false,
+		SupportsSkipLocked: true,
+		SupportsNoWait:     true,
+		SupportsLockOf:     false,
+
+		StatementHints:   StatementHintComment,
+		IndexHints:       IndexHintComment,
+		StatementTimeout: StatementTimeoutUnsupported,
+		Explain:          ExplainPlanFor,
+
+		LateralJoin:            LateralJoinApply,
+		TableFuncColumns:       TableFuncColumnsClause,
+		GroupingSets:           GroupingSetsStandard,
+		Concat:                 ConcatOperator,
+		Sequences:              SequencePseudoColumn,
+		LastInsertIDExpression: "",
+
+		LengthFunction:      "LENGTH",
+		Substring:           SubstringSubstr,
+		CeilFunction:        "CEIL",
+		Mod:                 ModFunction,
+		RandomExpression:    "DBMS_RANDOM.VALUE",
+		GreatestLeast:       GreatestLeastFunction,
+		NullReplaceFunction: "NVL",
+
+		Interval:           IntervalNumToDS,
+		DateTrunc:          DateTruncTrunc,
+		DatePart:           DatePartExtractToChar,
+		TimeZoneConversion: TimeZoneConversionFromTZ,
+		JSON:               JSONUnsupported,
+		FullTextSearch:     FullTextSearchUnsupported,
 	}
 }
 
 // NullCoalesce provides a Oracle specific implementation for COALESCE, especially for time.Time type.
 // Oracle uses NVL function which is similar to COALESCE but takes only two arguments.
 func (d Oracle) NullCoalesce(column sqlf.Builder, goType reflect.Type) (sqlf.Builder, error) {
-	if !CheckNullCoalesceable(goType) {
+	if !needsCoalesce(goType) {
 		return nil, nil
 	}
+	if zero, ok := sqlZeroOf(goType); ok {
+		return sqlf.F("NVL(?, ?)", column, zero), nil
+	}
+	if goType == jsonRawMessageType {
+		return sqlf.F("NVL(?, 'null')", column), nil
+	}
+	if isBinary(goType) {
+		// the empty RAW is NULL as well, and there is no sentinel for binaries
+		return nil, fmt.Errorf("%w: %s, Oracle treats empty binary as NULL", ErrUnsupportedNullCoalesceType, goType)
+	}
 	switch goType.Kind() {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
 		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
 		reflect.Float32, reflect.Float64:
 		return sqlf.F("NVL(?, 0)", column), nil
 	case reflect.String:
-		return sqlf.F("NVL(?, '')", column), nil
+		if d.EmptyStringValue == nil {
+			return sqlf.F("NVL(?, '')", column), nil
+		}
+		return sqlf.F("NVL(?, ?)", column, d.EmptyStringValue), nil
 	case reflect.Bool:
 		if d.BoolFalseValue == nil {
 			return sqlf.F("NVL(?, 0)", column), nil
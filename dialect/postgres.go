@@ -28,12 +28,68 @@ func (PostgreSQL) Capabilities() Capabilities {
 
 		SupportsUpdateFrom: true,
 		SupportsUpdateJoin: false,
+
+		LargeInListStrategy:             InListArray,
+		MaxInListSize:                   0,
+		ValuesRowConstructor:            "",
+		SupportsDerivedColumnList:       true,
+		SupportsBooleanLiteral:          true,
+		SupportsTupleIn:                 true,
+		SupportsTupleComparison:         true,
+		SupportsILike:                   true,
+		SupportsLikeCharClass:           false,
+		SupportsSetOperationAll:         true,
+		ExceptKeyword:                   "EXCEPT",
+		SupportsParenthesizedSetOperand: true,
+		RequiresRecursiveKeyword:        true,
+		RequiresRecursiveColumnList:     false,
+		SupportsCTEMaterialized:         true,
+		SupportsModifyingCTE:            true,
+		SupportsWindowClause:            true,
+		SupportsGroupsFrame:             true,
+		SupportsAggregateFilter:         true,
+		SupportsMultiColumnGrouping:     true,
+
+		Pagination: PaginationLimitOffset,
+
+		RowLocking:         RowLockingClause,
+		SupportsForShare:   true,
+		SupportsSkipLocked: true,
+		SupportsNoWait:     true,
+		SupportsLockOf:     true,
+
+		StatementHints:   StatementHintLeadingComment,
+		IndexHints:       IndexHintUnsupported,
+		StatementTimeout: StatementTimeoutSetLocal,
+		Explain:          ExplainOptionList,
+
+		LateralJoin:            LateralJoinLateral,
+		TableFuncColumns:       TableFuncColumnsAlias,
+		GroupingSets:           GroupingSetsStandard,
+		Concat:                 ConcatOperator,
+		Sequences:              SequenceNextValFunction,
+		LastInsertIDExpression: "lastval()",
+
+		LengthFunction:      "LENGTH",
+		Substring:           SubstringSubstr,
+		CeilFunction:        "CEIL",
+		Mod:                 ModFunction,
+		RandomExpression:    "RANDOM()",
+		GreatestLeast:       GreatestLeastFunction,
+		NullReplaceFunction: "",
+
+		Interval:           IntervalString,
+		DateTrunc:          DateTruncFunction,
+		DatePart:           DatePartExtractAll,
+		TimeZoneConversion: TimeZoneConversionAtTimeZone,
+		JSON:               JSONOperators,
+		FullTextSearch:     FullTextSearchTSVector,
 	}
 }
 
 // NullCoalesce provides a PostgreSQL specific implementation for COALESCE, especially for time.Time type.
 func (PostgreSQL) NullCoalesce(column sqlf.Builder, goType reflect.Type) (sqlf.Builder, error) {
-	if !CheckNullCoalesceable(goType) {
+	if !needsCoalesce(goType) {
 		return nil, nil
 	}
 	// Use AssignableTo to handle custom type aliases like `type MyTime time.Time`.
@@ -43,6 +99,10 @@ func (PostgreSQL) NullCoalesce(column sqlf.Builder, goType reflect.Type) (sqlf.B
 		// especially with 'timestamp with time zone' (timestamptz) columns.
 		return sqlf.F("COALESCE(?, '0001-01-01 00:00:00Z'::timestamptz)", column), nil
 	}
+	if isBinary(goType) {
+		// X'' is a bit string in PostgreSQL
+		return sqlf.F("COALESCE(?, ''::bytea)", column), nil
+	}
 	// Fallback to the generic ANSI implementation for other types
 	return AnsiSQL{}.NullCoalesce(column, goType)
 }
@@ -28,12 +28,68 @@ func (SQLite) Capabilities() Capabilities {
 
 		SupportsUpdateFrom: true,
 		SupportsUpdateJoin: false,
+
+		LargeInListStrategy:             InListValues,
+		MaxInListSize:                   0,
+		ValuesRowConstructor:            "",
+		SupportsDerivedColumnList:       false,
+		SupportsBooleanLiteral:          true,
+		SupportsTupleIn:                 false,
+		SupportsTupleComparison:         true,
+		SupportsILike:                   false,
+		SupportsLikeCharClass:           false,
+		SupportsSetOperationAll:         false,
+		ExceptKeyword:                   "EXCEPT",
+		SupportsParenthesizedSetOperand: false,
+		RequiresRecursiveKeyword:        true,
+		RequiresRecursiveColumnList:     false,
+		SupportsCTEMaterialized:         true,
+		SupportsModifyingCTE:            false,
+		SupportsWindowClause:            true,
+		SupportsGroupsFrame:             true,
+		SupportsAggregateFilter:         true,
+		SupportsMultiColumnGrouping:     false,
+
+		Pagination: PaginationLimitOffset,
+
+		RowLocking:         RowLockingUnsupported,
+		SupportsForShare:   false,
+		SupportsSkipLocked: false,
+		SupportsNoWait:     false,
+		SupportsLockOf:     false,
+
+		StatementHints:   StatementHintUnsupported,
+		IndexHints:       IndexHintIndexedBy,
+		StatementTimeout: StatementTimeoutUnsupported,
+		Explain:          ExplainQueryPlan,
+
+		LateralJoin:            LateralJoinUnsupported,
+		TableFuncColumns:       TableFuncColumnsUnsupported,
+		GroupingSets:           GroupingSetsUnsupported,
+		Concat:                 ConcatOperator,
+		Sequences:              SequenceUnsupported,
+		LastInsertIDExpression: "last_insert_rowid()",
+
+		LengthFunction:      "LENGTH",
+		Substring:           SubstringSubstr,
+		CeilFunction:        "CEIL",
+		Mod:                 ModOperator,
+		RandomExpression:    "RANDOM()",
+		GreatestLeast:       GreatestLeastMinMax,
+		NullReplaceFunction: "",
+
+		Interval:           IntervalModifier,
+		DateTrunc:          DateTruncModifier,
+		DatePart:           DatePartStrftime,
+		TimeZoneConversion: TimeZoneConversionUnsupported,
+		JSON:               JSON1,
+		FullTextSearch:     FullTextSearchFTS5,
 	}
 }
 
 // NullCoalesce provides a SQLite specific implementation for COALESCE, especially for time.Time type.
 func (d SQLite) NullCoalesce(column sqlf.Builder, goType reflect.Type) (sqlf.Builder, error) {
-	if !CheckNullCoalesceable(goType) {
+	if !needsCoalesce(goType) {
 		return nil, nil
 	}
 	// Use AssignableTo to handle custom type aliases.
@@ -28,12 +28,68 @@ func (SQLServer) Capabilities() Capabilities {
 
 		SupportsUpdateFrom: true,
 		SupportsUpdateJoin: false,
+
+		LargeInListStrategy:             InListJSON,
+		MaxInListSize:                   2000,
+		ValuesRowConstructor:            "",
+		SupportsDerivedColumnList:       true,
+		SupportsBooleanLiteral:          false,
+		SupportsTupleIn:                 false,
+		SupportsTupleComparison:         false,
+		SupportsILike:                   false,
+		SupportsLikeCharClass:           true,
+		SupportsSetOperationAll:         false,
+		ExceptKeyword:                   "EXCEPT",
+		SupportsParenthesizedSetOperand: true,
+		RequiresRecursiveKeyword:        false,
+		RequiresRecursiveColumnList:     false,
+		SupportsCTEMaterialized:         false,
+		SupportsModifyingCTE:            false,
+		SupportsWindowClause:            true,
+		SupportsGroupsFrame:             false,
+		SupportsAggregateFilter:         false,
+		SupportsMultiColumnGrouping:     false,
+
+		Pagination: PaginationTop,
+
+		RowLocking:         RowLockingTableHints,
+		SupportsForShare:   true,
+		SupportsSkipLocked: true,
+		SupportsNoWait:     true,
+		SupportsLockOf:     true,
+
+		StatementHints:   StatementHintOption,
+		IndexHints:       IndexHintTableHint,
+		StatementTimeout: StatementTimeoutUnsupported,
+		Explain:          ExplainShowPlan,
+
+		LateralJoin:            LateralJoinApply,
+		TableFuncColumns:       TableFuncColumnsWith,
+		GroupingSets:           GroupingSetsStandard,
+		Concat:                 ConcatPlus,
+		Sequences:              SequenceNextValueFor,
+		LastInsertIDExpression: "SCOPE_IDENTITY()",
+
+		LengthFunction:      "LEN",
+		Substring:           SubstringFunction,
+		CeilFunction:        "CEILING",
+		Mod:                 ModOperator,
+		RandomExpression:    "NEWID()",
+		GreatestLeast:       GreatestLeastFunction,
+		NullReplaceFunction: "",
+
+		Interval:           IntervalDatePart,
+		DateTrunc:          DateTruncDateDiff,
+		DatePart:           DatePartFunction,
+		TimeZoneConversion: TimeZoneConversionAtTimeZone,
+		JSON:               JSONOpenJSON,
+		FullTextSearch:     FullTextSearchContains,
 	}
 }
 
 // NullCoalesce provides a SQLServer specific implementation for COALESCE, especially for time.Time type.
 func (d SQLServer) NullCoalesce(column sqlf.Builder, goType reflect.Type) (sqlf.Builder, error) {
-	if !CheckNullCoalesceable(goType) {
+	if !needsCoalesce(goType) {
 		return nil, nil
 	}
 	// Use AssignableTo to handle custom type aliases.
@@ -48,6 +104,10 @@ func (d SQLServer) NullCoalesce(column sqlf.Builder, goType reflect.Type) (sqlf.
 		// SQL Server does not have a native boolean type, it uses BIT where 0 is false and 1 is true.
 		return sqlf.F("COALESCE(?, 0)", column), nil
 	}
+	if isBinary(goType) {
+		// the empty VARBINARY literal
+		return sqlf.F("COALESCE(?, 0x)", column), nil
+	}
 	// Fallback to the generic ANSI implementation for other types
 	return AnsiSQL{}.NullCoalesce(column, goType)
 }
@@ -0,0 +1,190 @@
+package sqlb
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/qjebbs/go-sqlb/dialect"
+)
+
+var _ Builder = (*ExplainBuilder)(nil)
+var _ StatementsBuilder = (*ExplainBuilder)(nil)
+
+// ExplainFormat is the output format of the plan.
+type ExplainFormat int
+
+// Explain formats.
+const (
+	// ExplainDefault is the default format of the dialect,
+	// e.g. text for PostgreSQL, XML for SQLServer.
+	ExplainDefault ExplainFormat = iota
+	ExplainJSON
+	ExplainXML
+)
+
+// ExplainOptions is the options for explaining a statement.
+type ExplainOptions struct {
+	// Analyze executes the statement and reports the actual run time statistics,
+	// which is supported by PostgreSQL, MySQL and SQLServer.
+	//
+	// !!! The statement is really executed, wrap the data-modifying statements
+	// in a transaction to roll them back.
+	Analyze bool
+	// Format is the output format of the plan.
+	Format ExplainFormat
+}
+
+// ExplainBuilder wraps a built statement in the plan command of the dialect.
+type ExplainBuilder struct {
+	builder Builder
+	opts    ExplainOptions
+}
+
+// Explain returns a builder which explains the query, see ExplainBuilder.
+func (b *SelectBuilder) Explain(opts ExplainOptions) *ExplainBuilder {
+	return &ExplainBuilder{builder: b, opts: opts}
+}
+
+// Explain returns a builder which explains the statement, see ExplainBuilder.
+func (b *InsertBuilder) Explain(opts ExplainOptions) *ExplainBuilder {
+	return &ExplainBuilder{builder: b, opts: opts}
+}
+
+// Explain returns a builder which explains the statement, see ExplainBuilder.
+func (b *UpdateBuilder) Explain(opts ExplainOptions) *ExplainBuilder {
+	return &ExplainBuilder{builder: b, opts: opts}
+}
+
+// Explain returns a builder which explains the statement, see ExplainBuilder.
+func (b *DeleteBuilder) Explain(opts ExplainOptions) *ExplainBuilder {
+	return &ExplainBuilder{builder: b, opts: opts}
+}
+
+// Build builds the explain statement, e.g.:
+//
+//	PostgreSQL: EXPLAIN (ANALYZE, FORMAT JSON) SELECT ...
+//	MySQL:      EXPLAIN FORMAT=JSON SELECT ...
+//	SQLite:     EXPLAIN QUERY PLAN SELECT ...
+//
+// The dialects explaining with multiple statements (SQLServer, Oracle)
+// must be built with BuildStatements, see dialect.ExplainStyle.
+func (b *ExplainBuilder) Build(ctx Context) (query string, args []any, err error) {
+	stmts, err := b.BuildStatements(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(stmts) != 1 {
+		return "", nil, fmt.Errorf("explain: %T requires building with BuildStatements", ctx.Dialect())
+	}
+	return stmts[0].Query, stmts[0].Args, nil
+}
+
+// BuildStatements builds the explain statements, which should be executed
+// in order on the same connection, e.g.:
+//
+//	SQLServer:
+//	  SET SHOWPLAN_XML ON
+//	  SELECT ...
+//	  SET SHOWPLAN_XML OFF
+//	Oracle:
+//	  EXPLAIN PLAN FOR SELECT ...
+//	  SELECT PLAN_TABLE_OUTPUT FROM TABLE(DBMS_XPLAN.DISPLAY())
+//
+// The setup statements of the explained builder are kept before the plan command.
+func (b *ExplainBuilder) BuildStatements(ctx Context) ([]Statement, error) {
+	// keep the setup statements, e.g. the timeout of PostgreSQL
+	var timeout time.Duration
+	switch sb := b.builder.(type) {
+	case *SelectBuilder:
+		timeout = sb.timeout
+	case *InsertBuilder:
+		timeout = sb.timeout
+	case *UpdateBuilder:
+		timeout = sb.timeout
+	case *DeleteBuilder:
+		timeout = sb.timeout
+	}
+	stmts, err := buildWithTimeout(ctx, b.builder, timeout)
+	if err != nil {
+		return nil, err
+	}
+	setup, target := stmts[:len(stmts)-1], stmts[len(stmts)-1]
+	explained, err := b.explain(ctx.Dialect(), target)
+	if err != nil {
+		return nil, err
+	}
+	return append(setup[:len(setup):len(setup)], explained...), nil
+}
+
+func (b *ExplainBuilder) explain(d dialect.Dialect, stmt Statement) ([]Statement, error) {
+	format := b.opts.Format
+	unsupported := func(option string) error {
+		return fmt.Errorf("explain: %s is not supported by %T", option, d)
+	}
+	switch d.Capabilities().Explain {
+	case dialect.ExplainOptionList:
+		var options []string
+		if b.opts.Analyze {
+			options = append(options, "ANALYZE")
+		}
+		switch format {
+		case ExplainJSON:
+			options = append(options, "FORMAT JSON")
+		case ExplainXML:
+			options = append(options, "FORMAT XML")
+		}
+		prefix := "EXPLAIN "
+		if len(options) > 0 {
+			prefix = "EXPLAIN (" + strings.Join(options, ", ") + ") "
+		}
+		return []Statement{{Query: prefix + stmt.Query, Args: stmt.Args}}, nil
+	case dialect.ExplainFormatOption:
+		prefix := "EXPLAIN "
+		switch {
+		case format == ExplainXML:
+			return nil, unsupported("XML format")
+		case b.opts.Analyze && format == ExplainJSON:
+			return nil, unsupported("ANALYZE with JSON format")
+		case b.opts.Analyze:
+			prefix = "EXPLAIN ANALYZE "
+		case format == ExplainJSON:
+			prefix = "EXPLAIN FORMAT=JSON "
+		}
+		return []Statement{{Query: prefix + stmt.Query, Args: stmt.Args}}, nil
+	case dialect.ExplainQueryPlan:
+		switch {
+		case b.opts.Analyze:
+			return nil, unsupported("ANALYZE")
+		case format != ExplainDefault:
+			return nil, unsupported("format")
+		}
+		return []Statement{{Query: "EXPLAIN QUERY PLAN " + stmt.Query, Args: stmt.Args}}, nil
+	case dialect.ExplainShowPlan:
+		if format == ExplainJSON {
+			return nil, unsupported("JSON format")
+		}
+		option := "SHOWPLAN_XML"
+		if b.opts.Analyze {
+			option = "STATISTICS XML"
+		}
+		return []Statement{
+			{Query: "SET " + option + " ON"},
+			stmt,
+			{Query: "SET " + option + " OFF"},
+		}, nil
+	case dialect.ExplainPlanFor:
+		switch {
+		case b.opts.Analyze:
+			return nil, unsupported("ANALYZE")
+		case format != ExplainDefault:
+			return nil, unsupported("format")
+		}
+		return []Statement{
+			{Query: "EXPLAIN PLAN FOR " + stmt.Query, Args: stmt.Args},
+			{Query: "SELECT PLAN_TABLE_OUTPUT FROM TABLE(DBMS_XPLAN.DISPLAY())"},
+		}, nil
+	default:
+		return nil, fmt.Errorf("explain: not supported by %T", d)
+	}
+}
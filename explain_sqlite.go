@@ -0,0 +1,165 @@
+package sqlb
+
+import (
+	"database/sql"
+	"strings"
+)
+
+// SQLitePlan is the parsed output of SQLite `EXPLAIN QUERY PLAN`,
+// which helps to write plan-regression tests, e.g.:
+//
+//	rows, err := db.Query(query, args...) // built by ExplainBuilder
+//	plan, err := sqlb.ScanSQLitePlan(rows)
+//	if scans := plan.FullScans(); len(scans) > 0 {
+//		t.Errorf("unexpected full table scans: %v\n%s", scans, plan)
+//	}
+type SQLitePlan struct {
+	// Nodes are the top level nodes of the plan.
+	Nodes []*SQLitePlanNode
+}
+
+// SQLitePlanNode is a node of the SQLite query plan.
+type SQLitePlanNode struct {
+	ID       int
+	Parent   int
+	Detail   string
+	Children []*SQLitePlanNode
+}
+
+// SQLitePlanRow is a row of SQLite `EXPLAIN QUERY PLAN` output.
+type SQLitePlanRow struct {
+	ID     int
+	Parent int
+	Detail string
+}
+
+// ScanSQLitePlan scans the rows of SQLite `EXPLAIN QUERY PLAN`,
+// which are (id, parent, notused, detail), and closes the rows.
+func ScanSQLitePlan(rows *sql.Rows) (*SQLitePlan, error) {
+	defer rows.Close()
+	var planRows []SQLitePlanRow
+	for rows.Next() {
+		var (
+			row     SQLitePlanRow
+			notused int
+		)
+		if err := rows.Scan(&row.ID, &row.Parent, &notused, &row.Detail); err != nil {
+			return nil, err
+		}
+		planRows = append(planRows, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return NewSQLitePlan(planRows), nil
+}
+
+// NewSQLitePlan builds the plan tree from the rows of `EXPLAIN QUERY PLAN`.
+func NewSQLitePlan(rows []SQLitePlanRow) *SQLitePlan {
+	plan := &SQLitePlan{}
+	nodes := make(map[int]*SQLitePlanNode, len(rows))
+	for _, row := range rows {
+		node := &SQLitePlanNode{
+			ID:     row.ID,
+			Parent: row.Parent,
+			Detail: row.Detail,
+		}
+		nodes[row.ID] = node
+		if parent, ok := nodes[row.Parent]; ok && row.Parent != row.ID {
+			parent.Children = append(parent.Children, node)
+		} else {
+			plan.Nodes = append(plan.Nodes, node)
+		}
+	}
+	return plan
+}
+
+// Walk calls fn for each node of the plan in depth-first order.
+func (p *SQLitePlan) Walk(fn func(node *SQLitePlanNode)) {
+	var walk func(nodes []*SQLitePlanNode)
+	walk = func(nodes []*SQLitePlanNode) {
+		for _, node := range nodes {
+			fn(node)
+			walk(node.Children)
+		}
+	}
+	walk(p.Nodes)
+}
+
+// FullScans returns the tables scanned without index, as reported by SQLite,
+// e.g. "foo" of `SCAN foo` and `SCAN TABLE foo AS f`.
+//
+// Note that SQLite 3.36 and later report the alias instead of the table
+// if the table is aliased, e.g. "f" of `SCAN f`.
+func (p *SQLitePlan) FullScans() []string {
+	var r []string
+	p.Walk(func(node *SQLitePlanNode) {
+		op, table, using := node.parse()
+		if op == "SCAN" && using == "" && table != "CONSTANT ROW" && !strings.Contains(node.Detail, "VIRTUAL TABLE") {
+			r = append(r, table)
+		}
+	})
+	return r
+}
+
+// Indexes returns the names of indexes used by the plan,
+// e.g. "idx_a" of `SEARCH foo USING INDEX idx_a (a=?)`.
+func (p *SQLitePlan) Indexes() []string {
+	var r []string
+	p.Walk(func(node *SQLitePlanNode) {
+		_, _, using := node.parse()
+		using = strings.TrimPrefix(using, "COVERING ")
+		if !strings.HasPrefix(using, "INDEX ") {
+			return
+		}
+		name, _, _ := strings.Cut(strings.TrimPrefix(using, "INDEX "), " ")
+		r = append(r, name)
+	})
+	return r
+}
+
+// UsesTempBTree reports whether the plan sorts or groups with temporary B-trees,
+// e.g. `USE TEMP B-TREE FOR ORDER BY`.
+func (p *SQLitePlan) UsesTempBTree() bool {
+	var found bool
+	p.Walk(func(node *SQLitePlanNode) {
+		if strings.HasPrefix(node.Detail, "USE TEMP B-TREE") {
+			found = true
+		}
+	})
+	return found
+}
+
+// String returns the plan tree in the format of the sqlite3 shell.
+func (p *SQLitePlan) String() string {
+	sb := new(strings.Builder)
+	sb.WriteString("QUERY PLAN")
+	var write func(nodes []*SQLitePlanNode, indent string)
+	write = func(nodes []*SQLitePlanNode, indent string) {
+		for i, node := range nodes {
+			branch, next := "|--", "|  "
+			if i == len(nodes)-1 {
+				branch, next = "`--", "   "
+			}
+			sb.WriteString("\n" + indent + branch + node.Detail)
+			write(node.Children, indent+next)
+		}
+	}
+	write(p.Nodes, "")
+	return sb.String()
+}
+
+// parse parses the detail like `SEARCH TABLE foo AS f USING INDEX idx (a=?)`
+// into operation "SEARCH", table "foo" and using "INDEX idx (a=?)".
+func (n *SQLitePlanNode) parse() (op, table, using string) {
+	op, rest, ok := strings.Cut(n.Detail, " ")
+	if !ok || (op != "SCAN" && op != "SEARCH") {
+		return op, "", ""
+	}
+	// older versions report `SCAN TABLE foo`
+	rest = strings.TrimPrefix(rest, "TABLE ")
+	table, using, _ = strings.Cut(rest, " USING ")
+	// older versions report the alias after the table
+	table, _, _ = strings.Cut(table, " AS ")
+	return op, table, using
+}
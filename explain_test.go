@@ -0,0 +1,178 @@
+package sqlb_test
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/qjebbs/go-sqlb"
+	"github.com/qjebbs/go-sqlb/dialect"
+	"github.com/qjebbs/go-sqlf/v4"
+)
+
+func TestExplainAnalyzeJSONPostgreSQL(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	q := sqlb.NewSelectBuilder().
+		Select(foo.Column("id")).
+		From(foo).
+		WhereEquals(foo.Column("a"), 1).
+		Explain(sqlb.ExplainOptions{Analyze: true, Format: sqlb.ExplainJSON})
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	got, err := q.BuildStatements(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []sqlb.Statement{
+		{Query: `EXPLAIN (ANALYZE, FORMAT JSON) SELECT "f"."id" FROM "foo" AS "f" WHERE "f"."a" = $1`, Args: []any{1}},
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want:\n%#v\ngot:\n%#v", want, got)
+	}
+}
+
+func TestExplainTimeoutPostgreSQL(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	q := sqlb.NewSelectBuilder().
+		Select(foo.Column("id")).
+		From(foo).
+		Timeout(time.Second).
+		Explain(sqlb.ExplainOptions{})
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	got, err := q.BuildStatements(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []sqlb.Statement{
+		{Query: "SET LOCAL statement_timeout = 1000"},
+		{Query: `EXPLAIN SELECT "f"."id" FROM "foo" AS "f"`},
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want:\n%#v\ngot:\n%#v", want, got)
+	}
+}
+
+func TestExplainWrappedDialect(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	q := sqlb.NewSelectBuilder().
+		Select(foo.Column("id")).
+		From(foo).
+		Explain(sqlb.ExplainOptions{Format: sqlb.ExplainJSON})
+	ctx := sqlb.NewContext(context.Background(), wrappedPostgreSQL{})
+	got, err := q.BuildStatements(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []sqlb.Statement{
+		{Query: `EXPLAIN (FORMAT JSON) SELECT "f"."id" FROM "foo" AS "f"`},
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want:\n%#v\ngot:\n%#v", want, got)
+	}
+}
+
+func TestExplainDeleteMySQL(t *testing.T) {
+	q := sqlb.NewDeleteBuilder().
+		DeleteFrom("foo").
+		WhereEquals(sqlf.Identifier("a"), 1).
+		Explain(sqlb.ExplainOptions{Format: sqlb.ExplainJSON})
+	ctx := sqlb.NewContext(context.Background(), dialect.MySQL{})
+	got, err := q.BuildStatements(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []sqlb.Statement{
+		{Query: "EXPLAIN FORMAT=JSON DELETE FROM `foo` WHERE `a` = ?", Args: []any{1}},
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want:\n%#v\ngot:\n%#v", want, got)
+	}
+}
+
+func TestExplainUpdateSQLite(t *testing.T) {
+	q := sqlb.NewUpdateBuilder().
+		Update("foo").
+		Set("a", 2).
+		Explain(sqlb.ExplainOptions{})
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLite{})
+	got, err := q.BuildStatements(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []sqlb.Statement{
+		{Query: `EXPLAIN QUERY PLAN UPDATE "foo" SET "a" = ?`, Args: []any{2}},
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want:\n%#v\ngot:\n%#v", want, got)
+	}
+}
+
+func TestExplainInsertSQLServer(t *testing.T) {
+	q := sqlb.NewInsertBuilder().
+		InsertInto("foo").
+		Columns("a").
+		Values(1).
+		Explain(sqlb.ExplainOptions{})
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLServer{})
+	got, err := q.BuildStatements(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []sqlb.Statement{
+		{Query: "SET SHOWPLAN_XML ON"},
+		{Query: "INSERT INTO [foo] ([a]) VALUES (@p1)", Args: []any{sql.Named("p1", 1)}},
+		{Query: "SET SHOWPLAN_XML OFF"},
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want:\n%#v\ngot:\n%#v", want, got)
+	}
+}
+
+func TestExplainErrors(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	sel := sqlb.NewSelectBuilder().
+		Select(foo.Column("id")).
+		From(foo)
+	// multiple statements are not allowed for Build()
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLServer{})
+	if _, _, err := sel.Explain(sqlb.ExplainOptions{}).Build(ctx); err == nil {
+		t.Error("want error for Build() on SQLServer, got nil")
+	}
+	ctx = sqlb.NewContext(context.Background(), dialect.SQLite{})
+	if _, _, err := sel.Explain(sqlb.ExplainOptions{Analyze: true}).Build(ctx); err == nil {
+		t.Error("want error for ANALYZE on SQLite, got nil")
+	}
+}
+
+func TestSQLitePlan(t *testing.T) {
+	plan := sqlb.NewSQLitePlan([]sqlb.SQLitePlanRow{
+		{ID: 3, Parent: 0, Detail: "SCAN f"},
+		{ID: 8, Parent: 0, Detail: "SEARCH b USING INDEX idx_bar_foo_id (foo_id=?)"},
+		{ID: 15, Parent: 0, Detail: "CORRELATED SCALAR SUBQUERY 1"},
+		{ID: 19, Parent: 15, Detail: "SCAN TABLE baz"},
+		{ID: 23, Parent: 15, Detail: "SEARCH qux USING INTEGER PRIMARY KEY (rowid=?)"},
+		{ID: 30, Parent: 0, Detail: "USE TEMP B-TREE FOR ORDER BY"},
+		{ID: 34, Parent: 0, Detail: "SCAN TABLE quux AS q"},
+	})
+	if got, want := plan.FullScans(), []string{"f", "baz", "quux"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got full scans %v, want %v", got, want)
+	}
+	if got, want := plan.Indexes(), []string{"idx_bar_foo_id"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got indexes %v, want %v", got, want)
+	}
+	if !plan.UsesTempBTree() {
+		t.Error("want temp b-tree, got none")
+	}
+	want := "QUERY PLAN\n" +
+		"|--SCAN f\n" +
+		"|--SEARCH b USING INDEX idx_bar_foo_id (foo_id=?)\n" +
+		"|--CORRELATED SCALAR SUBQUERY 1\n" +
+		"|  |--SCAN TABLE baz\n" +
+		"|  `--SEARCH qux USING INTEGER PRIMARY KEY (rowid=?)\n" +
+		"|--USE TEMP B-TREE FOR ORDER BY\n" +
+		"`--SCAN TABLE quux AS q"
+	if got := plan.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,138 @@
+package fn
+
+import (
+	"fmt"
+
+	"github.com/qjebbs/go-sqlb/dialect"
+	"github.com/qjebbs/go-sqlb/internal/util"
+	"github.com/qjebbs/go-sqlf/v4"
+)
+
+// Grouping returns the bitmask telling which of the columns are aggregated
+// in the subtotal rows of ROLLUP, CUBE and GROUPING SETS, e.g. `GROUPING(a, b)`.
+// It's built as `GROUPING_ID(a, b)` for SQL Server and Oracle if there are
+// multiple columns, see dialect.Capabilities.SupportsMultiColumnGrouping,
+// and not supported by SQLite.
+func Grouping(columns ...any) sqlf.Builder {
+	return sqlf.Func(func(ctx sqlf.Context) (string, error) {
+		caps := dialectOf(ctx).Capabilities()
+		if caps.GroupingSets == dialect.GroupingSetsUnsupported {
+			return "", fmt.Errorf("GROUPING is not supported by %T", ctx.BaseDialect())
+		}
+		if len(columns) > 1 && !caps.SupportsMultiColumnGrouping {
+			return call("GROUPING_ID", columns...).BuildTo(ctx)
+		}
+		return call("GROUPING", columns...).BuildTo(ctx)
+	})
+}
+
+var _ sqlf.Builder = (*AggregateBuilder)(nil)
+
+// AggregateBuilder builds an aggregate function call, which can be
+// filtered with a condition, see AggregateBuilder.Filter.
+type AggregateBuilder struct {
+	name     string
+	x        any // nil for COUNT(*)
+	distinct bool
+	filters  []sqlf.Builder
+}
+
+// CountAll returns the aggregate `COUNT(*)`.
+func CountAll() *AggregateBuilder {
+	return &AggregateBuilder{name: "COUNT"}
+}
+
+// Count returns the aggregate `COUNT(x)`, which counts the non-NULL values.
+func Count(x any) *AggregateBuilder {
+	return &AggregateBuilder{name: "COUNT", x: x}
+}
+
+// Sum returns the aggregate `SUM(x)`.
+func Sum(x any) *AggregateBuilder {
+	return &AggregateBuilder{name: "SUM", x: x}
+}
+
+// Avg returns the aggregate `AVG(x)`.
+func Avg(x any) *AggregateBuilder {
+	return &AggregateBuilder{name: "AVG", x: x}
+}
+
+// Min returns the aggregate `MIN(x)`.
+func Min(x any) *AggregateBuilder {
+	return &AggregateBuilder{name: "MIN", x: x}
+}
+
+// Max returns the aggregate `MAX(x)`.
+func Max(x any) *AggregateBuilder {
+	return &AggregateBuilder{name: "MAX", x: x}
+}
+
+// Distinct aggregates the distinct values only, e.g. `COUNT(DISTINCT x)`.
+func (b *AggregateBuilder) Distinct() *AggregateBuilder {
+	b.distinct = true
+	return b
+}
+
+// Filter aggregates the rows matching the condition only, which is built as
+// the FILTER clause for PostgreSQL and SQLite, and emulated with the CASE
+// expression for others, e.g.:
+//
+//	foo := sqlb.NewTable("foo", "f")
+//	fn.CountAll().Filter(sqlf.F("? = ?", foo.Column("status"), "x"))
+//	// PostgreSQL: COUNT(*) FILTER (WHERE "f"."status" = $1)
+//	// MySQL:      COUNT(CASE WHEN `f`.`status` = ? THEN 1 END)
+//	fn.Max(foo.Column("price")).Filter(sqlf.F("? = ?", foo.Column("status"), "x"))
+//	// PostgreSQL: MAX("f"."price") FILTER (WHERE "f"."status" = $1)
+//	// MySQL:      MAX(CASE WHEN `f`.`status` = ? THEN `f`.`price` END)
+//
+// The filtered COUNT(*) is emulated as `COUNT(CASE WHEN ... THEN 1 END)` rather
+// than `SUM(CASE WHEN ... THEN 1 ELSE 0 END)`, since SUM returns NULL instead
+// of 0 when there are no rows.
+//
+// Multiple calls are joined with AND, in which each condition is parenthesized.
+func (b *AggregateBuilder) Filter(cond sqlf.Builder) *AggregateBuilder {
+	b.filters = append(b.filters, cond)
+	return b
+}
+
+// BuildTo implements sqlf.Builder
+func (b *AggregateBuilder) BuildTo(ctx sqlf.Context) (string, error) {
+	prefix := b.name + "("
+	if b.distinct {
+		if b.x == nil {
+			return "", fmt.Errorf("%s(DISTINCT *) is not allowed", b.name)
+		}
+		prefix += "DISTINCT "
+	}
+	if len(b.filters) == 0 {
+		return sqlf.F(prefix+"?)", b.value()).BuildTo(ctx)
+	}
+	if dialectOf(ctx).Capabilities().SupportsAggregateFilter {
+		return sqlf.F(prefix+"?) FILTER (WHERE ?)", b.value(), b.condition()).BuildTo(ctx)
+	}
+	// NULLs are ignored by aggregates, so that the unmatched rows are skipped
+	// by the CASE expression without ELSE.
+	then := b.value()
+	if b.x == nil {
+		then = sqlf.F("1")
+	}
+	return sqlf.F(prefix+"CASE WHEN ? THEN ? END)", b.condition(), then).BuildTo(ctx)
+}
+
+// condition returns the filter conditions joined with AND.
+func (b *AggregateBuilder) condition() sqlf.Builder {
+	if len(b.filters) == 1 {
+		return b.filters[0]
+	}
+	return sqlf.Join(util.Map(b.filters, func(cond sqlf.Builder) sqlf.Builder {
+		return sqlf.F("(?)", cond)
+	}), " AND ")
+}
+
+// value returns the aggregated value, which is `*` for COUNT(*).
+func (b *AggregateBuilder) value() sqlf.Builder {
+	if b.x == nil {
+		return sqlf.F("*")
+	}
+	return arg(b.x)
+}
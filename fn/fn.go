@@ -0,0 +1,48 @@
+// Package fn provides portable SQL scalar and aggregate functions, which choose
+// their rendering according to the dialect of the building context.
+//
+// All functions accept mixed arguments, which are either sqlf.Builder
+// (e.g. table columns) or ordinary values to be bound as args:
+//
+//	foo := sqlb.NewTable("foo", "f")
+//	fn.Concat(foo.Column("first_name"), " ", foo.Column("last_name"))
+//	// PostgreSQL: ("f"."first_name" || $1 || "f"."last_name")
+//	// MySQL:      CONCAT(`f`.`first_name`, ?, `f`.`last_name`)
+//	// SQLServer:  ([f].[first_name] + @p1 + [f].[last_name])
+//
+// Table references in the arguments are reported to the dependency
+// tracking of sqlb builders as usual.
+package fn
+
+import (
+	"fmt"
+
+	"github.com/qjebbs/go-sqlb/dialect"
+	"github.com/qjebbs/go-sqlf/v4"
+)
+
+// dialectOf returns the sqlb dialect of the context,
+// falling back to AnsiSQL if it cannot be upgraded.
+func dialectOf(ctx sqlf.Context) dialect.Dialect {
+	if d, ok := dialect.Upgrade(ctx.BaseDialect()); ok {
+		return d
+	}
+	return dialect.AnsiSQL{}
+}
+
+// call builds a function call like `name(arg1, arg2)`.
+func call(name string, args ...any) sqlf.Builder {
+	return sqlf.F(name+"(?)", sqlf.JoinMixed(args, ", "))
+}
+
+// arg converts a mixed argument to a builder.
+func arg(a any) sqlf.Builder {
+	if b, ok := a.(sqlf.Builder); ok {
+		return b
+	}
+	return sqlf.F("?", a)
+}
+
+func errUnsupported(fn string, d dialect.Dialect) error {
+	return fmt.Errorf("fn: %s is not supported for dialect %T", fn, d)
+}
@@ -0,0 +1,512 @@
+package fn_test
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/qjebbs/go-sqlb"
+	"github.com/qjebbs/go-sqlb/dialect"
+	"github.com/qjebbs/go-sqlb/fn"
+	"github.com/qjebbs/go-sqlf/v4"
+)
+
+func TestConcatPostgreSQL(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, fn.Concat(foo.Column("name"), "-", foo.Column("id")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `("f"."name" || $1 || "f"."id")`
+	wantArgs := []any{"-"}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestConcatMySQL(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	ctx := sqlb.NewContext(context.Background(), dialect.MySQL{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, fn.Concat(foo.Column("name"), "-", foo.Column("id")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := "CONCAT(`f`.`name`, ?, `f`.`id`)"
+	wantArgs := []any{"-"}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestConcatSQLServer(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLServer{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, fn.Concat(foo.Column("name"), "-", foo.Column("id")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `([f].[name] + @p1 + [f].[id])`
+	wantArgs := []any{sql.Named("p1", "-")}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestLengthSQLite(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLite{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, fn.Length(foo.Column("name")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `LENGTH("f"."name")`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestLengthMySQL(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	ctx := sqlb.NewContext(context.Background(), dialect.MySQL{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, fn.Length(foo.Column("name")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := "CHAR_LENGTH(`f`.`name`)"
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestLengthSQLServer(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLServer{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, fn.Length(foo.Column("name")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `LEN([f].[name])`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSubstringOracle(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	ctx := sqlb.NewContext(context.Background(), dialect.Oracle{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, fn.Substring(foo.Column("name"), 1, 3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SUBSTR("f"."name", :1, :2)`
+	wantArgs := []any{1, 3}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSubstringSQLServer(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLServer{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, fn.Substring(foo.Column("name"), 1, 3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SUBSTRING([f].[name], @p1, @p2)`
+	wantArgs := []any{sql.Named("p1", 1), sql.Named("p2", 3)}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestRandomPostgreSQL(t *testing.T) {
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, fn.Random())
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `RANDOM()`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestRandomMySQL(t *testing.T) {
+	ctx := sqlb.NewContext(context.Background(), dialect.MySQL{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, fn.Random())
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `RAND()`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestRandomSQLServer(t *testing.T) {
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLServer{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, fn.Random())
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `NEWID()`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestRandomOracle(t *testing.T) {
+	ctx := sqlb.NewContext(context.Background(), dialect.Oracle{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, fn.Random())
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `DBMS_RANDOM.VALUE`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestCoalescePostgreSQL(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, fn.Coalesce(foo.Column("name"), ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `COALESCE("f"."name", $1)`
+	wantArgs := []any{""}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestCoalesceOracle(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	ctx := sqlb.NewContext(context.Background(), dialect.Oracle{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, fn.Coalesce(foo.Column("name"), ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `NVL("f"."name", :1)`
+	wantArgs := []any{""}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestModPostgreSQL(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, fn.Mod(foo.Column("id"), 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `MOD("f"."id", $1)`
+	wantArgs := []any{2}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestModSQLite(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLite{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, fn.Mod(foo.Column("id"), 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `("f"."id" % ?)`
+	wantArgs := []any{2}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestGreatestSQLite(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLite{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, fn.Greatest(foo.Column("a"), fn.Ceil(foo.Column("b"))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `MAX("f"."a", CEIL("f"."b"))`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestGreatestSQLServer(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLServer{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, fn.Greatest(foo.Column("a"), fn.Ceil(foo.Column("b"))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `GREATEST([f].[a], CEILING([f].[b]))`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestRound(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	ctx := sqlb.NewContext(context.Background(), dialect.MySQL{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, fn.Round(foo.Column("price"), 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := "ROUND(`f`.`price`, 2)"
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSum(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	ctx := sqlb.NewContext(context.Background(), dialect.MySQL{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, fn.Sum(foo.Column("price")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := "SUM(`f`.`price`)"
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestGroupingPostgreSQL(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, fn.Grouping(foo.Column("a"), foo.Column("b")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `GROUPING("f"."a", "f"."b")`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestGroupingSQLServer(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLServer{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, fn.Grouping(foo.Column("a"), foo.Column("b")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `GROUPING_ID([f].[a], [f].[b])`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+// wrappedSQLServer is a custom dialect embedding a built-in one.
+type wrappedSQLServer struct {
+	dialect.SQLServer
+}
+
+func TestFunctionsWrappedDialect(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	ctx := sqlb.NewContext(context.Background(), wrappedSQLServer{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, fn.Mod(fn.Length(foo.Column("name")), 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `(LEN([f].[name]) % @p1)`
+	wantArgs := []any{sql.Named("p1", 2)}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestFunctionsUnsupported(t *testing.T) {
+	ctx := sqlb.NewContext(context.Background(), dialect.MySQL{})
+	if _, _, err := sqlf.Build(ctx, fn.Concat()); err == nil {
+		t.Error("Concat(): want error, got nil")
+	}
+	ctx = sqlb.NewContext(context.Background(), dialect.AnsiSQL{})
+	if _, _, err := sqlf.Build(ctx, fn.Random()); err == nil {
+		t.Error("Random(): want error, got nil")
+	}
+}
+
+func TestFunctionsDependencies(t *testing.T) {
+	var (
+		foo = sqlb.NewTable("foo", "f")
+		bar = sqlb.NewTable("bar", "b")
+		baz = sqlb.NewTable("baz", "z")
+	)
+	b := sqlb.NewSelectBuilder().
+		EnableElimination().
+		Distinct().
+		Select(fn.Coalesce(bar.Column("name"), foo.Column("name"))).
+		From(foo).
+		LeftJoinOptional(bar, sqlf.F("? = ?", bar.Column("id"), foo.Column("bar_id"))).
+		LeftJoinOptional(baz, sqlf.F("? = ?", baz.Column("id"), foo.Column("baz_id")))
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := b.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT DISTINCT COALESCE("b"."name", "f"."name") FROM "foo" AS "f" LEFT JOIN "bar" AS "b" ON "b"."id" = "f"."bar_id"`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestAggregateFilterDependencies(t *testing.T) {
+	var (
+		foo = sqlb.NewTable("foo", "f")
+		bar = sqlb.NewTable("bar", "b")
+	)
+	b := sqlb.NewSelectBuilder().
+		EnableElimination().
+		Select(fn.CountAll().Filter(sqlf.F("? = ?", bar.Column("status"), "x"))).
+		From(foo).
+		LeftJoinOptional(bar, sqlf.F("? = ?", bar.Column("id"), foo.Column("bar_id")))
+	ctx := sqlb.NewContext(context.Background(), dialect.MySQL{})
+	gotQuery, gotArgs, err := b.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := "SELECT COUNT(CASE WHEN `b`.`status` = ? THEN 1 END) FROM `foo` AS `f` LEFT JOIN `bar` AS `b` ON `b`.`id` = `f`.`bar_id`"
+	wantArgs := []any{"x"}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestAggregateFilter(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	b := fn.Max(foo.Column("price")).
+		Filter(sqlf.F("? = ?", foo.Column("status"), "x")).
+		Filter(sqlf.F("? > ? OR ? IS NULL", foo.Column("id"), 1, foo.Column("id")))
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `MAX("f"."price") FILTER (WHERE ("f"."status" = $1) AND ("f"."id" > $2 OR "f"."id" IS NULL))`
+	wantArgs := []any{"x", 1}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestAggregateFilterEmulated(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	b := sqlf.Join([]sqlf.Builder{
+		fn.CountAll().Filter(sqlf.F("? = ?", foo.Column("status"), "x")),
+		fn.Count(foo.Column("id")).Distinct().
+			Filter(sqlf.F("? = ?", foo.Column("status"), "y")).
+			Filter(sqlf.F("? > ? OR ? IS NULL", foo.Column("id"), 1, foo.Column("id"))),
+	}, ", ")
+	ctx := sqlb.NewContext(context.Background(), dialect.MySQL{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := "COUNT(CASE WHEN `f`.`status` = ? THEN 1 END), COUNT(DISTINCT CASE WHEN (`f`.`status` = ?) AND (`f`.`id` > ? OR `f`.`id` IS NULL) THEN `f`.`id` END)"
+	wantArgs := []any{"x", "y", 1}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
@@ -0,0 +1,117 @@
+package fn
+
+import (
+	"fmt"
+
+	"github.com/qjebbs/go-sqlb/dialect"
+	"github.com/qjebbs/go-sqlf/v4"
+)
+
+// Abs returns the absolute value of x.
+func Abs(x any) sqlf.Builder {
+	return call("ABS", x)
+}
+
+// Round rounds x to the given decimal places.
+func Round(x any, places int) sqlf.Builder {
+	return sqlf.F(fmt.Sprintf("ROUND(?, %d)", places), arg(x))
+}
+
+// Ceil returns the smallest integer not less than x,
+// which is built as `CEILING(x)` for SQL Server and `CEIL(x)` for others,
+// see dialect.Capabilities.CeilFunction.
+func Ceil(x any) sqlf.Builder {
+	return sqlf.Func(func(ctx sqlf.Context) (string, error) {
+		d := dialectOf(ctx)
+		name := d.Capabilities().CeilFunction
+		if name == "" {
+			return "", errUnsupported("Ceil", d)
+		}
+		return call(name, x).BuildTo(ctx)
+	})
+}
+
+// Floor returns the largest integer not greater than x.
+func Floor(x any) sqlf.Builder {
+	return call("FLOOR", x)
+}
+
+// Mod returns the remainder of x divided by y, which is built as
+// `(x % y)` for SQL Server and SQLite, and `MOD(x, y)` for others,
+// see dialect.ModStyle.
+func Mod(x, y any) sqlf.Builder {
+	return sqlf.Func(func(ctx sqlf.Context) (string, error) {
+		d := dialectOf(ctx)
+		switch d.Capabilities().Mod {
+		case dialect.ModOperator:
+			return sqlf.F("(? % ?)", arg(x), arg(y)).BuildTo(ctx)
+		case dialect.ModFunction:
+			return call("MOD", x, y).BuildTo(ctx)
+		default:
+			return "", errUnsupported("Mod", d)
+		}
+	})
+}
+
+// Power returns x raised to the power of y.
+//
+// For SQLite, it requires the math functions to be enabled at compile time.
+func Power(x, y any) sqlf.Builder {
+	return call("POWER", x, y)
+}
+
+// Random returns a random value, which is mostly used to shuffle rows, e.g.:
+//
+//	b.OrderBy(fn.Random())
+//
+// It's built as `RANDOM()` for PostgreSQL and SQLite, `RAND()` for MySQL,
+// `NEWID()` for SQL Server, and `DBMS_RANDOM.VALUE` for Oracle, and
+// not supported by ANSI SQL, see dialect.Capabilities.RandomExpression.
+//
+// Note that the type and range of the value vary across dialects, e.g.
+// SQLite returns a random integer, and SQL Server returns a uniqueidentifier,
+// since RAND() of SQL Server is evaluated only once per query.
+func Random() sqlf.Builder {
+	return sqlf.Func(func(ctx sqlf.Context) (string, error) {
+		d := dialectOf(ctx)
+		expr := d.Capabilities().RandomExpression
+		if expr == "" {
+			return "", errUnsupported("Random", d)
+		}
+		return expr, nil
+	})
+}
+
+// Greatest returns the largest value of the arguments, which is built as
+// `MAX(a, b)` for SQLite and `GREATEST(a, b)` for others, and not
+// supported by ANSI SQL, see dialect.GreatestLeastStyle.
+func Greatest(args ...any) sqlf.Builder {
+	return sqlf.Func(func(ctx sqlf.Context) (string, error) {
+		d := dialectOf(ctx)
+		switch d.Capabilities().GreatestLeast {
+		case dialect.GreatestLeastMinMax:
+			return call("MAX", args...).BuildTo(ctx)
+		case dialect.GreatestLeastFunction:
+			return call("GREATEST", args...).BuildTo(ctx)
+		default:
+			return "", errUnsupported("Greatest", d)
+		}
+	})
+}
+
+// Least returns the smallest value of the arguments, which is built as
+// `MIN(a, b)` for SQLite and `LEAST(a, b)` for others, and not
+// supported by ANSI SQL, see dialect.GreatestLeastStyle.
+func Least(args ...any) sqlf.Builder {
+	return sqlf.Func(func(ctx sqlf.Context) (string, error) {
+		d := dialectOf(ctx)
+		switch d.Capabilities().GreatestLeast {
+		case dialect.GreatestLeastMinMax:
+			return call("MIN", args...).BuildTo(ctx)
+		case dialect.GreatestLeastFunction:
+			return call("LEAST", args...).BuildTo(ctx)
+		default:
+			return "", errUnsupported("Least", d)
+		}
+	})
+}
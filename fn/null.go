@@ -0,0 +1,23 @@
+package fn
+
+import (
+	"github.com/qjebbs/go-sqlf/v4"
+)
+
+// Coalesce returns the first non-NULL argument, which is built as
+// `NVL(a, b)` for Oracle with two arguments, and `COALESCE(a, b, ...)` for others,
+// see dialect.Capabilities.NullReplaceFunction.
+func Coalesce(args ...any) sqlf.Builder {
+	return sqlf.Func(func(ctx sqlf.Context) (string, error) {
+		name := dialectOf(ctx).Capabilities().NullReplaceFunction
+		if name != "" && len(args) == 2 {
+			return call(name, args...).BuildTo(ctx)
+		}
+		return call("COALESCE", args...).BuildTo(ctx)
+	})
+}
+
+// NullIf returns NULL if a equals b, otherwise a.
+func NullIf(a, b any) sqlf.Builder {
+	return call("NULLIF", a, b)
+}
@@ -0,0 +1,90 @@
+package fn
+
+import (
+	"fmt"
+
+	"github.com/qjebbs/go-sqlb/dialect"
+	"github.com/qjebbs/go-sqlb/internal/util"
+	"github.com/qjebbs/go-sqlf/v4"
+)
+
+// Concat concatenates the arguments into a string, which is built as
+// `(a || b)` for PostgreSQL, SQLite and Oracle, `CONCAT(a, b)` for MySQL,
+// and `(a + b)` for SQL Server, see dialect.ConcatStyle.
+//
+// The result is NULL if any of the arguments is NULL, except for Oracle,
+// which treats NULL as the empty string.
+func Concat(args ...any) sqlf.Builder {
+	return sqlf.Func(func(ctx sqlf.Context) (string, error) {
+		d := dialectOf(ctx)
+		if len(args) == 0 {
+			return "", fmt.Errorf("fn: Concat requires at least one argument")
+		}
+		switch d.Capabilities().Concat {
+		case dialect.ConcatOperator:
+			return sqlf.F("(?)", sqlf.Join(util.Map(args, arg), " || ")).BuildTo(ctx)
+		case dialect.ConcatFunction:
+			return call("CONCAT", args...).BuildTo(ctx)
+		case dialect.ConcatPlus:
+			return sqlf.F("(?)", sqlf.Join(util.Map(args, arg), " + ")).BuildTo(ctx)
+		default:
+			return "", errUnsupported("Concat", d)
+		}
+	})
+}
+
+// Length returns the number of characters in the string, which is built as
+// `CHAR_LENGTH(s)` for MySQL and ANSI SQL, `LEN(s)` for SQL Server and
+// `LENGTH(s)` for others, see dialect.Capabilities.LengthFunction.
+//
+// Note that LEN() of SQL Server excludes trailing spaces.
+func Length(s any) sqlf.Builder {
+	return sqlf.Func(func(ctx sqlf.Context) (string, error) {
+		d := dialectOf(ctx)
+		name := d.Capabilities().LengthFunction
+		if name == "" {
+			return "", errUnsupported("Length", d)
+		}
+		return call(name, s).BuildTo(ctx)
+	})
+}
+
+// Substring returns the substring of s starting at the 1-based start position
+// with the given length, which is built as `SUBSTRING(s, start, length)` for
+// MySQL and SQL Server, `SUBSTRING(s FROM start FOR length)` for ANSI SQL,
+// and `SUBSTR(s, start, length)` for others, see dialect.SubstringStyle.
+func Substring(s, start, length any) sqlf.Builder {
+	return sqlf.Func(func(ctx sqlf.Context) (string, error) {
+		d := dialectOf(ctx)
+		switch d.Capabilities().Substring {
+		case dialect.SubstringFunction:
+			return call("SUBSTRING", s, start, length).BuildTo(ctx)
+		case dialect.SubstringFromFor:
+			return sqlf.F("SUBSTRING(? FROM ? FOR ?)", arg(s), arg(start), arg(length)).BuildTo(ctx)
+		case dialect.SubstringSubstr:
+			return call("SUBSTR", s, start, length).BuildTo(ctx)
+		default:
+			return "", errUnsupported("Substring", d)
+		}
+	})
+}
+
+// Lower converts the string to lower case.
+func Lower(s any) sqlf.Builder {
+	return call("LOWER", s)
+}
+
+// Upper converts the string to upper case.
+func Upper(s any) sqlf.Builder {
+	return call("UPPER", s)
+}
+
+// Trim removes the leading and trailing spaces of the string.
+func Trim(s any) sqlf.Builder {
+	return call("TRIM", s)
+}
+
+// Replace replaces all occurrences of from in s with to.
+func Replace(s, from, to any) sqlf.Builder {
+	return call("REPLACE", s, from, to)
+}
@@ -0,0 +1,94 @@
+package sqlb
+
+import (
+	"fmt"
+
+	"github.com/qjebbs/go-sqlb/dialect"
+	"github.com/qjebbs/go-sqlb/internal/util"
+	"github.com/qjebbs/go-sqlf/v4"
+)
+
+var _ sqlf.Builder = (*groupingElement)(nil)
+
+// groupingElement is a GROUP BY element of multiple grouping sets,
+// e.g. ROLLUP(a, b), CUBE(a, b) or GROUPING SETS ((a, b), ()).
+type groupingElement struct {
+	kind    string // ROLLUP, CUBE or GROUPING SETS
+	columns []sqlf.Builder
+	sets    [][]sqlf.Builder
+}
+
+// Rollup returns the GROUP BY element `ROLLUP(a, b)`, which groups by
+// (a, b), (a) and the grand total. For MySQL, it's built as `a, b WITH ROLLUP`
+// and must be the only GROUP BY element.
+//
+//	foo := sqlb.NewTable("foo")
+//	b.GroupBy(sqlb.Rollup(foo.Column("a"), foo.Column("b")))
+func Rollup(columns ...sqlf.Builder) sqlf.Builder {
+	return &groupingElement{kind: "ROLLUP", columns: columns}
+}
+
+// Cube returns the GROUP BY element `CUBE(a, b)`, which groups by
+// all the combinations of the columns. It's not supported by MySQL and SQLite.
+func Cube(columns ...sqlf.Builder) sqlf.Builder {
+	return &groupingElement{kind: "CUBE", columns: columns}
+}
+
+// GroupingSets returns the GROUP BY element `GROUPING SETS (...)`, in which
+// an empty set is the grand total. It's not supported by MySQL and SQLite.
+//
+//	foo := sqlb.NewTable("foo")
+//	b.GroupBy(sqlb.GroupingSets(
+//		[]sqlf.Builder{foo.Column("a"), foo.Column("b")},
+//		[]sqlf.Builder{foo.Column("a")},
+//		nil,
+//	))
+//	// GROUP BY GROUPING SETS (("foo"."a", "foo"."b"), ("foo"."a"), ())
+func GroupingSets(sets ...[]sqlf.Builder) sqlf.Builder {
+	return &groupingElement{kind: "GROUPING SETS", sets: sets}
+}
+
+// BuildTo implements sqlf.Builder
+func (g *groupingElement) BuildTo(ctx sqlf.Context) (string, error) {
+	uCtx, err := contextUpgrade(ctx)
+	if err != nil {
+		return "", err
+	}
+	switch uCtx.Dialect().Capabilities().GroupingSets {
+	case dialect.GroupingSetsStandard:
+		if g.sets != nil {
+			return sqlf.F("GROUPING SETS (?)", sqlf.Join(util.Map(g.sets, func(set []sqlf.Builder) sqlf.Builder {
+				return sqlf.F("(?)", sqlf.Join(set, ", "))
+			}), ", ")).BuildTo(ctx)
+		}
+		if len(g.columns) == 0 {
+			return "", fmt.Errorf("%s: no columns specified", g.kind)
+		}
+		return sqlf.F(g.kind+"(?)", sqlf.Join(g.columns, ", ")).BuildTo(ctx)
+	case dialect.GroupingSetsWithRollup:
+		if g.kind != "ROLLUP" {
+			return "", fmt.Errorf("%s is not supported by %T", g.kind, uCtx.BaseDialect())
+		}
+		if len(g.columns) == 0 {
+			return "", fmt.Errorf("%s: no columns specified", g.kind)
+		}
+		return sqlf.F("? WITH ROLLUP", sqlf.Join(g.columns, ", ")).BuildTo(ctx)
+	default:
+		return "", fmt.Errorf("%s is not supported by %T", g.kind, uCtx.BaseDialect())
+	}
+}
+
+// checkGroupingElements checks the GROUP BY elements, since `WITH ROLLUP`
+// rolls up the whole GROUP BY list, which differs from ROLLUP() combined
+// with other elements.
+func checkGroupingElements(ctx Context, elements []sqlf.Builder) error {
+	if len(elements) < 2 || ctx.Dialect().Capabilities().GroupingSets != dialect.GroupingSetsWithRollup {
+		return nil
+	}
+	for _, e := range elements {
+		if g, ok := e.(*groupingElement); ok {
+			return fmt.Errorf("%s must be the only GROUP BY element for %T", g.kind, ctx.BaseDialect())
+		}
+	}
+	return nil
+}
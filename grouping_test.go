@@ -0,0 +1,172 @@
+package sqlb_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/qjebbs/go-sqlb"
+	"github.com/qjebbs/go-sqlb/dialect"
+	"github.com/qjebbs/go-sqlb/fn"
+	"github.com/qjebbs/go-sqlf/v4"
+)
+
+func TestGroupByRollup(t *testing.T) {
+	var (
+		sales   = sqlb.NewTable("sales", "s")
+		regions = sqlb.NewTable("regions", "r")
+	)
+	region, product := regions.Column("name"), sales.Column("product")
+	q := sqlb.NewSelectBuilder().
+		EnableElimination().
+		Select(fn.Grouping(product), sqlf.F("SUM(?)", sales.Column("amount"))).
+		From(sales).
+		LeftJoinOptional(regions, sqlf.F("? = ?", regions.Column("id"), sales.Column("region_id"))).
+		Where(sqlf.F("? > ?", sales.Column("amount"), 0)).
+		GroupBy(sqlb.Rollup(region, product))
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT GROUPING("s"."product"), SUM("s"."amount") FROM "sales" AS "s" LEFT JOIN "regions" AS "r" ON "r"."id" = "s"."region_id" WHERE "s"."amount" > $1 GROUP BY ROLLUP("r"."name", "s"."product")`
+	wantArgs := []any{0}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestGroupByRollupMySQL(t *testing.T) {
+	var (
+		sales   = sqlb.NewTable("sales", "s")
+		regions = sqlb.NewTable("regions", "r")
+	)
+	region, product := regions.Column("name"), sales.Column("product")
+	q := sqlb.NewSelectBuilder().
+		EnableElimination().
+		Select(fn.Grouping(region, product), sqlf.F("SUM(?)", sales.Column("amount"))).
+		From(sales).
+		LeftJoinOptional(regions, sqlf.F("? = ?", regions.Column("id"), sales.Column("region_id"))).
+		GroupBy(sqlb.Rollup(region, product))
+	ctx := sqlb.NewContext(context.Background(), dialect.MySQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := "SELECT GROUPING(`r`.`name`, `s`.`product`), SUM(`s`.`amount`) FROM `sales` AS `s` LEFT JOIN `regions` AS `r` ON `r`.`id` = `s`.`region_id` GROUP BY `r`.`name`, `s`.`product` WITH ROLLUP"
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestGroupByCube(t *testing.T) {
+	var (
+		sales   = sqlb.NewTable("sales", "s")
+		regions = sqlb.NewTable("regions", "r")
+	)
+	region, product := regions.Column("name"), sales.Column("product")
+	q := sqlb.NewSelectBuilder().
+		EnableElimination().
+		Select(fn.Grouping(region, product), sqlf.F("SUM(?)", sales.Column("amount"))).
+		From(sales).
+		LeftJoinOptional(regions, sqlf.F("? = ?", regions.Column("id"), sales.Column("region_id"))).
+		GroupBy(sqlb.Cube(region, product))
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLServer{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT GROUPING_ID([r].[name], [s].[product]), SUM([s].[amount]) FROM [sales] AS [s] LEFT JOIN [regions] AS [r] ON [r].[id] = [s].[region_id] GROUP BY CUBE([r].[name], [s].[product])`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestGroupByGroupingSets(t *testing.T) {
+	var (
+		sales   = sqlb.NewTable("sales", "s")
+		regions = sqlb.NewTable("regions", "r")
+	)
+	region, product := regions.Column("name"), sales.Column("product")
+	q := sqlb.NewSelectBuilder().
+		EnableElimination().
+		Select(fn.Grouping(product), sqlf.F("SUM(?)", sales.Column("amount"))).
+		From(sales).
+		LeftJoinOptional(regions, sqlf.F("? = ?", regions.Column("id"), sales.Column("region_id"))).
+		GroupBy(sqlb.GroupingSets([]sqlf.Builder{region, product}, []sqlf.Builder{product}, nil))
+	ctx := sqlb.NewContext(context.Background(), dialect.Oracle{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT GROUPING("s"."product"), SUM("s"."amount") FROM "sales" AS "s" LEFT JOIN "regions" AS "r" ON "r"."id" = "s"."region_id" GROUP BY GROUPING SETS (("r"."name", "s"."product"), ("s"."product"), ())`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestGroupByRollupWithColumns(t *testing.T) {
+	var (
+		sales   = sqlb.NewTable("sales", "s")
+		regions = sqlb.NewTable("regions", "r")
+	)
+	region, product := regions.Column("name"), sales.Column("product")
+	q := sqlb.NewSelectBuilder().
+		EnableElimination().
+		Select(fn.Grouping(product), sqlf.F("SUM(?)", sales.Column("amount"))).
+		From(sales).
+		LeftJoinOptional(regions, sqlf.F("? = ?", regions.Column("id"), sales.Column("region_id"))).
+		GroupBy(product, sqlb.Rollup(region))
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT GROUPING("s"."product"), SUM("s"."amount") FROM "sales" AS "s" LEFT JOIN "regions" AS "r" ON "r"."id" = "s"."region_id" GROUP BY "s"."product", ROLLUP("r"."name")`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+	// WITH ROLLUP of MySQL rolls up the whole GROUP BY list
+	ctx = sqlb.NewContext(context.Background(), dialect.MySQL{})
+	if _, _, err := q.Build(ctx); err == nil {
+		t.Error("MySQL: want error, got nil")
+	}
+}
+
+func TestGroupingSetsUnsupported(t *testing.T) {
+	sales := sqlb.NewTable("sales", "s")
+	product := sales.Column("product")
+	newBuilder := func(group sqlf.Builder) *sqlb.SelectBuilder {
+		return sqlb.NewSelectBuilder().
+			Select(product, sqlf.F("SUM(?)", sales.Column("amount"))).
+			From(sales).
+			GroupBy(group)
+	}
+	ctx := sqlb.NewContext(context.Background(), dialect.MySQL{})
+	if _, _, err := newBuilder(sqlb.Cube(product)).Build(ctx); err == nil {
+		t.Error("MySQL CUBE: want error, got nil")
+	}
+	ctx = sqlb.NewContext(context.Background(), dialect.SQLite{})
+	if _, _, err := newBuilder(sqlb.Rollup(product)).Build(ctx); err == nil {
+		t.Error("SQLite ROLLUP: want error, got nil")
+	}
+}
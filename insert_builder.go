@@ -1,6 +1,8 @@
 package sqlb
 
 import (
+	"time"
+
 	"github.com/qjebbs/go-sqlb/internal/util"
 	"github.com/qjebbs/go-sqlf/v4"
 )
@@ -20,6 +22,7 @@ type InsertBuilder struct {
 	conflictOn []sqlf.Builder // conflict target
 	conflictDo []sqlf.Builder // conflict do action
 	returning  []sqlf.Builder // returning columns
+	timeout    time.Duration  // execution time limit
 
 	errors []error // errors during building
 
@@ -79,8 +82,19 @@ func (b *InsertBuilder) Returning(columns ...string) *InsertBuilder {
 // The CTE will be automatically eliminated if all the conditions below are met:
 //   - Pruning is enabled by `b.EnableElimination()` or parent builders
 //   - The table is not referenced anywhere in the query
-func (b *InsertBuilder) With(name Table, builder sqlf.Builder) *InsertBuilder {
-	b.ctes.With(name, builder)
+//
+// The optional CTEOptions declares the column names and the materialization hint, e.g.:
+//
+//	b.With(t, builder, sqlb.CTEOptions{Columns: []string{"a", "b"}, Materialized: sqlb.CTEMaterialized})
+//	// "t" ("a", "b") AS MATERIALIZED (...)
+func (b *InsertBuilder) With(name Table, builder sqlf.Builder, opts ...CTEOptions) *InsertBuilder {
+	b.ctes.With(name, builder, opts...)
+	return b
+}
+
+// WithRecursive adds a recursive common table expression, see SelectBuilder.WithRecursive.
+func (b *InsertBuilder) WithRecursive(name Table, columns []string, anchor, recursive sqlf.Builder) *InsertBuilder {
+	b.ctes.WithRecursive(name, columns, anchor, recursive)
 	return b
 }
 
@@ -58,33 +58,36 @@ func (b *InsertBuilder) buildInternal(ctx Context) (string, error) {
 		return "", fmt.Errorf("cannot specify both select and values for insert")
 	}
 
+	nested := isNestedStatement(ctx)
+	if !nested {
+		// the timeout applies to the top-level statement only
+		if err := checkTimeout(ctx, b.timeout); err != nil {
+			return "", err
+		}
+	}
+	ctx = contextWithNestedStatement(ctx)
 	ctx, pruning := decideContextPruning(ctx, b.pruning)
 	built := make([]string, 0)
-	if b.selects != nil && b.ctes.HasCTE() {
-		var err error
-		myDeps := newDependencies(b.name)
+	if b.ctes.HasCTE() {
+		var required map[string]bool
 		if pruning {
-			myDeps, err = b.collectDependencies(ctx)
+			myDeps, err := b.collectDependencies(ctx)
+			if err != nil {
+				return "", err
+			}
+			ctes, unresolved, err := b.ctes.CollectDependenciesForDeps(ctx, myDeps)
 			if err != nil {
 				return "", err
 			}
 			if deps := dependenciesFromContext(ctx); deps != nil {
-				for t := range myDeps.OuterTables {
-					deps.OuterTables[t] = true
-				}
-				for t := range myDeps.SourceNames {
-					deps.SourceNames[t] = true
-				}
+				deps.Merge(unresolved)
 				// collecting dependencies only,
 				// no need to build anything here
 				return "", nil
 			}
+			required = ctes
 		}
-		ctes := make(map[string]bool)
-		for cte := range myDeps.SourceNames {
-			ctes[cte] = true
-		}
-		with, err := b.ctes.BuildRequired(ctx, ctes)
+		with, err := b.ctes.BuildRequired(ctx, required, nested)
 		if err != nil {
 			return "", err
 		}
@@ -180,6 +183,8 @@ func (b *InsertBuilder) buildInternal(ctx Context) (string, error) {
 			built = append(built, returning)
 		case caps.SupportsOutputInserted:
 			// already built
+		case ctx.Value(emulatedReturningKey{}) != nil:
+			// built as a following statement, see BuildStatements
 		default:
 			return "", fmt.Errorf("returning is not supported for dialact %T", ctx.BaseDialect())
 		}
@@ -196,9 +201,18 @@ func (b *InsertBuilder) collectDependencies(ctx Context) (*dependencies, error)
 	// use a separate context to avoid polluting args
 	ctx = sqlf.ContextWithNewArgStore(ctx)
 	depCtx := contextWithDependencies(ctx, myDeps)
-	_, err := b.selects.BuildTo(depCtx)
-	if err != nil {
-		return nil, err
+	if b.selects != nil {
+		_, err := b.selects.BuildTo(depCtx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// values can be builders referencing CTEs, e.g. subqueries
+	for _, values := range b.values {
+		_, err := sqlf.JoinMixed(values, ", ").BuildTo(depCtx)
+		if err != nil {
+			return nil, err
+		}
 	}
 	return myDeps, nil
 }
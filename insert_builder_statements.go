@@ -0,0 +1,52 @@
+package sqlb
+
+import (
+	"fmt"
+
+	"github.com/qjebbs/go-sqlf/v4"
+)
+
+var _ StatementsBuilder = (*InsertBuilder)(nil)
+
+type emulatedReturningKey struct{}
+
+// BuildStatements builds the insert statement.
+//
+// On the dialects without RETURNING / OUTPUT (e.g. MySQL), the returning
+// column is emulated by a following statement, which must be executed
+// on the same connection right after the insert:
+//
+//	b.InsertInto("foo").Columns("name").Values("bar").Returning("id")
+//	// MySQL:
+//	//   INSERT INTO `foo` (`name`) VALUES (?)
+//	//   SELECT LAST_INSERT_ID() AS `id`
+//
+// The emulation is limited to single-row inserts with one returning column,
+// which should be the auto-increment column. It fails on the dialects without
+// LastInsertID, e.g. Oracle.
+//
+// The setup statements of Timeout() are prepended if required by the dialect.
+func (b *InsertBuilder) BuildStatements(ctx Context) ([]Statement, error) {
+	caps := ctx.Dialect().Capabilities()
+	if len(b.returning) == 0 || caps.SupportsReturning || caps.SupportsOutputInserted {
+		return buildWithTimeout(ctx, b, b.timeout)
+	}
+	if len(b.returning) > 1 {
+		return nil, fmt.Errorf("emulated returning: only one column is supported for %T", ctx.Dialect())
+	}
+	if b.selects != nil || len(b.values) != 1 {
+		return nil, fmt.Errorf("emulated returning: only single-row insert is supported for %T", ctx.Dialect())
+	}
+	query, args, err := b.Build(ContextWithValue(ctx, emulatedReturningKey{}, struct{}{}))
+	if err != nil {
+		return nil, err
+	}
+	idQuery, idArgs, err := sqlf.Build(ctx, sqlf.F("SELECT ? AS ?", LastInsertID(), b.returning[0]))
+	if err != nil {
+		return nil, err
+	}
+	return []Statement{
+		{Query: query, Args: args},
+		{Query: idQuery, Args: idArgs},
+	}, nil
+}
@@ -0,0 +1,314 @@
+package sqlb
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/qjebbs/go-sqlb/dialect"
+	"github.com/qjebbs/go-sqlf/v4"
+)
+
+var _ sqlf.Builder = JSONColumn{}
+
+// JSONColumn is a column storing JSON documents, e.g. PostgreSQL jsonb,
+// MySQL JSON, SQLite JSON1 text or SQLServer NVARCHAR.
+//
+// It builds the column reference itself, and provides builders for
+// the portable JSON path access and JSON predicates.
+//
+// The path elements are either object keys (string) or array indexes (int):
+//
+//	doc := t.JSONColumn("doc")
+//	doc.Text("tags", 0)
+//	// PostgreSQL: ("t"."doc" -> $1 ->> 0)
+//	// MySQL:      JSON_UNQUOTE(JSON_EXTRACT(`t`.`doc`, ?))  -- '$.tags[0]'
+//	// SQLite:     json_extract("t"."doc", ?)                -- '$.tags[0]'
+//	// SQLServer:  JSON_VALUE([t].[doc], @p1)                -- '$.tags[0]'
+type JSONColumn struct {
+	column sqlf.Builder
+}
+
+// JSONColumn returns a JSON column of the table.
+//
+// For example:
+//
+//	t := NewTable("table", "t")
+//	t.JSONColumn("doc").Text("name")
+func (t Table) JSONColumn(name string) JSONColumn {
+	return JSONColumn{
+		column: t.Column(name),
+	}
+}
+
+// BuildTo implements sqlf.Builder
+func (c JSONColumn) BuildTo(ctx sqlf.Context) (string, error) {
+	return c.column.BuildTo(ctx)
+}
+
+// Text returns the value at the path as text, JSON strings unquoted.
+func (c JSONColumn) Text(path ...any) sqlf.Builder {
+	return c.build("Text", path, func(d dialect.Dialect, p *jsonPath) (sqlf.Builder, error) {
+		switch d.Capabilities().JSON {
+		case dialect.JSONOperators:
+			return p.postgres(c.column, true), nil
+		case dialect.JSONFunctions:
+			return sqlf.F("JSON_UNQUOTE(JSON_EXTRACT(?, ?))", c.column, p.String()), nil
+		case dialect.JSON1:
+			return sqlf.F("json_extract(?, ?)", c.column, p.String()), nil
+		case dialect.JSONOpenJSON:
+			return sqlf.F("JSON_VALUE(?, ?)", c.column, p.String()), nil
+		}
+		return nil, errJSONUnsupported("Text", d)
+	})
+}
+
+// Extract returns the value at the path as JSON.
+//
+// On SQLServer, the value at the path must be an object or an array.
+func (c JSONColumn) Extract(path ...any) sqlf.Builder {
+	return c.build("Extract", path, func(d dialect.Dialect, p *jsonPath) (sqlf.Builder, error) {
+		switch d.Capabilities().JSON {
+		case dialect.JSONOperators:
+			return p.postgres(c.column, false), nil
+		case dialect.JSONFunctions:
+			return sqlf.F("JSON_EXTRACT(?, ?)", c.column, p.String()), nil
+		case dialect.JSON1:
+			return sqlf.F("(? -> ?)", c.column, p.String()), nil
+		case dialect.JSONOpenJSON:
+			return sqlf.F("JSON_QUERY(?, ?)", c.column, p.String()), nil
+		}
+		return nil, errJSONUnsupported("Extract", d)
+	})
+}
+
+// Int returns the value at the path as an integer.
+func (c JSONColumn) Int(path ...any) sqlf.Builder {
+	return c.cast("Int", path, jsonCastTypes{
+		postgres:  "BIGINT",
+		mysql:     "SIGNED",
+		sqlite:    "INTEGER",
+		sqlserver: "BIGINT",
+	})
+}
+
+// Float returns the value at the path as a floating point number.
+func (c JSONColumn) Float(path ...any) sqlf.Builder {
+	return c.cast("Float", path, jsonCastTypes{
+		postgres:  "DOUBLE PRECISION",
+		mysql:     "DOUBLE",
+		sqlite:    "REAL",
+		sqlserver: "FLOAT",
+	})
+}
+
+// Contains returns a predicate reporting whether the document contains
+// the value, which is encoded with encoding/json. Use json.RawMessage
+// for the pre-encoded documents.
+//
+// It's supported by PostgreSQL and MySQL only, see dialect.JSONStyle.
+func (c JSONColumn) Contains(value any) sqlf.Builder {
+	return sqlf.Func(func(ctx sqlf.Context) (string, error) {
+		uCtx, err := contextUpgrade(ctx)
+		if err != nil {
+			return "", err
+		}
+		doc, err := json.Marshal(value)
+		if err != nil {
+			return "", fmt.Errorf("json Contains: %w", err)
+		}
+		switch d := uCtx.Dialect(); d.Capabilities().JSON {
+		case dialect.JSONOperators:
+			return sqlf.F("? @> ?", c.column, string(doc)).BuildTo(uCtx)
+		case dialect.JSONFunctions:
+			return sqlf.F("JSON_CONTAINS(?, ?)", c.column, string(doc)).BuildTo(uCtx)
+		default:
+			return "", errJSONUnsupported("Contains", d)
+		}
+	})
+}
+
+// HasKey returns a predicate reporting whether the path exists in the document,
+// even if the value at the path is JSON null.
+func (c JSONColumn) HasKey(path ...any) sqlf.Builder {
+	return c.build("HasKey", path, func(d dialect.Dialect, p *jsonPath) (sqlf.Builder, error) {
+		if len(p.elems) == 0 {
+			return nil, fmt.Errorf("json HasKey: empty path")
+		}
+		switch d.Capabilities().JSON {
+		case dialect.JSONOperators:
+			// `->` returns SQL NULL only if the path is missing
+			return sqlf.F("? IS NOT NULL", p.postgres(c.column, false)), nil
+		case dialect.JSONFunctions:
+			return sqlf.F("JSON_CONTAINS_PATH(?, 'one', ?)", c.column, p.String()), nil
+		case dialect.JSON1:
+			return sqlf.F("json_type(?, ?) IS NOT NULL", c.column, p.String()), nil
+		case dialect.JSONOpenJSON:
+			parent := &jsonPath{elems: p.elems[:len(p.elems)-1]}
+			return sqlf.F(
+				"EXISTS (SELECT 1 FROM OPENJSON(?, ?) WHERE [key] = ?)",
+				c.column, parent.String(), fmt.Sprint(p.elems[len(p.elems)-1]),
+			), nil
+		}
+		return nil, errJSONUnsupported("HasKey", d)
+	})
+}
+
+// ArrayLength returns the length of the array at the path.
+func (c JSONColumn) ArrayLength(path ...any) sqlf.Builder {
+	return c.build("ArrayLength", path, func(d dialect.Dialect, p *jsonPath) (sqlf.Builder, error) {
+		switch d.Capabilities().JSON {
+		case dialect.JSONOperators:
+			return sqlf.F("jsonb_array_length(?)", p.postgres(c.column, false)), nil
+		case dialect.JSONFunctions:
+			return sqlf.F("JSON_LENGTH(?, ?)", c.column, p.String()), nil
+		case dialect.JSON1:
+			return sqlf.F("json_array_length(?, ?)", c.column, p.String()), nil
+		case dialect.JSONOpenJSON:
+			return sqlf.F("(SELECT COUNT(*) FROM OPENJSON(?, ?))", c.column, p.String()), nil
+		}
+		return nil, errJSONUnsupported("ArrayLength", d)
+	})
+}
+
+// jsonCastTypes are the target types of typed extraction per dialect.JSONStyle.
+type jsonCastTypes struct {
+	postgres, mysql, sqlite, sqlserver string
+}
+
+func (c JSONColumn) cast(fn string, path []any, types jsonCastTypes) sqlf.Builder {
+	text := c.Text(path...)
+	return sqlf.Func(func(ctx sqlf.Context) (string, error) {
+		uCtx, err := contextUpgrade(ctx)
+		if err != nil {
+			return "", err
+		}
+		var typ string
+		switch d := uCtx.Dialect(); d.Capabilities().JSON {
+		case dialect.JSONOperators:
+			typ = types.postgres
+		case dialect.JSONFunctions:
+			typ = types.mysql
+		case dialect.JSON1:
+			typ = types.sqlite
+		case dialect.JSONOpenJSON:
+			typ = types.sqlserver
+		default:
+			return "", errJSONUnsupported(fn, d)
+		}
+		return sqlf.F("CAST(? AS "+typ+")", text).BuildTo(uCtx)
+	})
+}
+
+func (c JSONColumn) build(fn string, path []any, f func(d dialect.Dialect, p *jsonPath) (sqlf.Builder, error)) sqlf.Builder {
+	return sqlf.Func(func(ctx sqlf.Context) (string, error) {
+		uCtx, err := contextUpgrade(ctx)
+		if err != nil {
+			return "", err
+		}
+		p, err := newJSONPath(path)
+		if err != nil {
+			return "", fmt.Errorf("json %s: %w", fn, err)
+		}
+		b, err := f(uCtx.Dialect(), p)
+		if err != nil {
+			return "", err
+		}
+		return b.BuildTo(uCtx)
+	})
+}
+
+func errJSONUnsupported(fn string, d dialect.Dialect) error {
+	return fmt.Errorf("json %s: unsupported dialect %T", fn, d)
+}
+
+// jsonPath is a validated JSON path, whose elements are
+// either object keys (string) or array indexes (int).
+type jsonPath struct {
+	elems []any
+}
+
+func newJSONPath(path []any) (*jsonPath, error) {
+	elems := make([]any, 0, len(path))
+	for _, e := range path {
+		switch e := e.(type) {
+		case string:
+			if strings.ContainsAny(e, `"\`) {
+				return nil, fmt.Errorf("unsupported key %q", e)
+			}
+			elems = append(elems, e)
+		case int:
+			if e < 0 {
+				return nil, fmt.Errorf("negative array index %d", e)
+			}
+			elems = append(elems, e)
+		default:
+			return nil, fmt.Errorf("invalid path element %v (%T), want string or int", e, e)
+		}
+	}
+	return &jsonPath{elems: elems}, nil
+}
+
+// String returns the SQL/JSON path expression like `$.a."b c"[0]`,
+// used by MySQL, SQLite and SQLServer.
+func (p *jsonPath) String() string {
+	sb := new(strings.Builder)
+	sb.WriteRune('$')
+	for _, e := range p.elems {
+		switch e := e.(type) {
+		case int:
+			fmt.Fprintf(sb, "[%d]", e)
+		case string:
+			sb.WriteRune('.')
+			if isSimpleJSONKey(e) {
+				sb.WriteString(e)
+			} else {
+				sb.WriteString(`"` + e + `"`)
+			}
+		}
+	}
+	return sb.String()
+}
+
+// postgres builds the PostgreSQL operator chain like `(col -> $1 ->> 0)`.
+// The keys are bound as args, and the indexes are inlined, since the
+// `->` operator is overloaded for text and integer.
+func (p *jsonPath) postgres(column sqlf.Builder, text bool) sqlf.Builder {
+	if len(p.elems) == 0 {
+		if text {
+			return sqlf.F("(? #>> '{}')", column)
+		}
+		return column
+	}
+	query := "(?"
+	args := []any{column}
+	for i, e := range p.elems {
+		op := " -> "
+		if text && i == len(p.elems)-1 {
+			op = " ->> "
+		}
+		switch e := e.(type) {
+		case int:
+			query += op + fmt.Sprint(e)
+		default:
+			query += op + "?"
+			args = append(args, e)
+		}
+	}
+	return sqlf.F(query+")", args...)
+}
+
+func isSimpleJSONKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i, r := range key {
+		switch {
+		case r == '_', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case i > 0 && r >= '0' && r <= '9':
+		default:
+			return false
+		}
+	}
+	return true
+}
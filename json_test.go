@@ -0,0 +1,410 @@
+package sqlb_test
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/qjebbs/go-sqlb"
+	"github.com/qjebbs/go-sqlb/dialect"
+	"github.com/qjebbs/go-sqlf/v4"
+)
+
+func TestJSONColumnTextPostgreSQL(t *testing.T) {
+	doc := sqlb.NewTable("foo", "f").JSONColumn("doc")
+	b := doc.Text("tags", 0)
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `("f"."doc" -> $1 ->> 0)`
+	wantArgs := []any{"tags"}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestJSONColumnTextMySQL(t *testing.T) {
+	doc := sqlb.NewTable("foo", "f").JSONColumn("doc")
+	b := doc.Text("tags", 0)
+	ctx := sqlb.NewContext(context.Background(), dialect.MySQL{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := "JSON_UNQUOTE(JSON_EXTRACT(`f`.`doc`, ?))"
+	wantArgs := []any{"$.tags[0]"}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestJSONColumnTextSQLite(t *testing.T) {
+	doc := sqlb.NewTable("foo", "f").JSONColumn("doc")
+	b := doc.Text("tags", 0)
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLite{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `json_extract("f"."doc", ?)`
+	wantArgs := []any{"$.tags[0]"}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestJSONColumnTextSQLServer(t *testing.T) {
+	doc := sqlb.NewTable("foo", "f").JSONColumn("doc")
+	b := doc.Text("tags", 0)
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLServer{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `JSON_VALUE([f].[doc], @p1)`
+	wantArgs := []any{sql.Named("p1", "$.tags[0]")}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestJSONColumnExtractQuotedKeyPostgreSQL(t *testing.T) {
+	doc := sqlb.NewTable("foo", "f").JSONColumn("doc")
+	b := doc.Extract("a b", "c")
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `("f"."doc" -> $1 -> $2)`
+	wantArgs := []any{"a b", "c"}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestJSONColumnExtractQuotedKeyMySQL(t *testing.T) {
+	doc := sqlb.NewTable("foo", "f").JSONColumn("doc")
+	b := doc.Extract("a b", "c")
+	ctx := sqlb.NewContext(context.Background(), dialect.MySQL{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := "JSON_EXTRACT(`f`.`doc`, ?)"
+	wantArgs := []any{`$."a b".c`}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestJSONColumnExtractQuotedKeySQLite(t *testing.T) {
+	doc := sqlb.NewTable("foo", "f").JSONColumn("doc")
+	b := doc.Extract("a b", "c")
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLite{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `("f"."doc" -> ?)`
+	wantArgs := []any{`$."a b".c`}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestJSONColumnExtractQuotedKeySQLServer(t *testing.T) {
+	doc := sqlb.NewTable("foo", "f").JSONColumn("doc")
+	b := doc.Extract("a b", "c")
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLServer{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `JSON_QUERY([f].[doc], @p1)`
+	wantArgs := []any{sql.Named("p1", `$."a b".c`)}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestJSONColumnIntPostgreSQL(t *testing.T) {
+	doc := sqlb.NewTable("foo", "f").JSONColumn("doc")
+	b := doc.Int("age")
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `CAST(("f"."doc" ->> $1) AS BIGINT)`
+	wantArgs := []any{"age"}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestJSONColumnIntMySQL(t *testing.T) {
+	doc := sqlb.NewTable("foo", "f").JSONColumn("doc")
+	b := doc.Int("age")
+	ctx := sqlb.NewContext(context.Background(), dialect.MySQL{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := "CAST(JSON_UNQUOTE(JSON_EXTRACT(`f`.`doc`, ?)) AS SIGNED)"
+	wantArgs := []any{"$.age"}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestJSONColumnContainsPostgreSQL(t *testing.T) {
+	doc := sqlb.NewTable("foo", "f").JSONColumn("doc")
+	b := doc.Contains(map[string]any{"tags": []string{"go"}})
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `"f"."doc" @> $1`
+	wantArgs := []any{`{"tags":["go"]}`}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestJSONColumnContainsMySQL(t *testing.T) {
+	doc := sqlb.NewTable("foo", "f").JSONColumn("doc")
+	b := doc.Contains(map[string]any{"tags": []string{"go"}})
+	ctx := sqlb.NewContext(context.Background(), dialect.MySQL{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := "JSON_CONTAINS(`f`.`doc`, ?)"
+	wantArgs := []any{`{"tags":["go"]}`}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestJSONColumnHasKeyPostgreSQL(t *testing.T) {
+	doc := sqlb.NewTable("foo", "f").JSONColumn("doc")
+	b := doc.HasKey("a", "b")
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `("f"."doc" -> $1 -> $2) IS NOT NULL`
+	wantArgs := []any{"a", "b"}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestJSONColumnHasKeyMySQL(t *testing.T) {
+	doc := sqlb.NewTable("foo", "f").JSONColumn("doc")
+	b := doc.HasKey("a", "b")
+	ctx := sqlb.NewContext(context.Background(), dialect.MySQL{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := "JSON_CONTAINS_PATH(`f`.`doc`, 'one', ?)"
+	wantArgs := []any{"$.a.b"}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestJSONColumnHasKeySQLite(t *testing.T) {
+	doc := sqlb.NewTable("foo", "f").JSONColumn("doc")
+	b := doc.HasKey("a", "b")
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLite{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `json_type("f"."doc", ?) IS NOT NULL`
+	wantArgs := []any{"$.a.b"}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestJSONColumnHasKeySQLServer(t *testing.T) {
+	doc := sqlb.NewTable("foo", "f").JSONColumn("doc")
+	b := doc.HasKey("a", "b")
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLServer{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `EXISTS (SELECT 1 FROM OPENJSON([f].[doc], @p1) WHERE [key] = @p2)`
+	wantArgs := []any{sql.Named("p1", "$.a"), sql.Named("p2", "b")}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestJSONColumnArrayLengthPostgreSQL(t *testing.T) {
+	doc := sqlb.NewTable("foo", "f").JSONColumn("doc")
+	b := doc.ArrayLength("tags")
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `jsonb_array_length(("f"."doc" -> $1))`
+	wantArgs := []any{"tags"}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestJSONColumnArrayLengthMySQL(t *testing.T) {
+	doc := sqlb.NewTable("foo", "f").JSONColumn("doc")
+	b := doc.ArrayLength("tags")
+	ctx := sqlb.NewContext(context.Background(), dialect.MySQL{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := "JSON_LENGTH(`f`.`doc`, ?)"
+	wantArgs := []any{"$.tags"}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestJSONColumnArrayLengthSQLite(t *testing.T) {
+	doc := sqlb.NewTable("foo", "f").JSONColumn("doc")
+	b := doc.ArrayLength("tags")
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLite{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `json_array_length("f"."doc", ?)`
+	wantArgs := []any{"$.tags"}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestJSONColumnArrayLengthSQLServer(t *testing.T) {
+	doc := sqlb.NewTable("foo", "f").JSONColumn("doc")
+	b := doc.ArrayLength("tags")
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLServer{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `(SELECT COUNT(*) FROM OPENJSON([f].[doc], @p1))`
+	wantArgs := []any{sql.Named("p1", "$.tags")}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestJSONColumnContainsWrappedDialect(t *testing.T) {
+	doc := sqlb.NewTable("foo", "f").JSONColumn("doc")
+	b := doc.Contains(map[string]any{"a": 1})
+	ctx := sqlb.NewContext(context.Background(), wrappedPostgreSQL{})
+	gotQuery, gotArgs, err := sqlf.Build(ctx, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `"f"."doc" @> $1`
+	wantArgs := []any{`{"a":1}`}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestJSONColumnErrors(t *testing.T) {
+	doc := sqlb.NewTable("foo", "f").JSONColumn("doc")
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLite{})
+	if _, _, err := sqlf.Build(ctx, doc.Contains([]int{1})); err == nil {
+		t.Error("SQLite Contains: want error, got nil")
+	}
+	ctx = sqlb.NewContext(context.Background(), dialect.Oracle{})
+	if _, _, err := sqlf.Build(ctx, doc.Text("a")); err == nil {
+		t.Error("Oracle Text: want error, got nil")
+	}
+	ctx = sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	if _, _, err := sqlf.Build(ctx, doc.Text(1.5)); err == nil {
+		t.Error("invalid path: want error, got nil")
+	}
+	ctx = sqlb.NewContext(context.Background(), dialect.MySQL{})
+	if _, _, err := sqlf.Build(ctx, doc.HasKey()); err == nil {
+		t.Error("HasKey with empty path: want error, got nil")
+	}
+}
@@ -0,0 +1,62 @@
+package sqlb
+
+import (
+	"fmt"
+
+	"github.com/qjebbs/go-sqlb/dialect"
+)
+
+// pagination is the built LIMIT / OFFSET of a query.
+type pagination struct {
+	// top is the TOP clause placed after `SELECT [DISTINCT]`, e.g. `TOP 10`
+	top string
+	// clauses are placed after ORDER BY, e.g. `LIMIT 10`, `OFFSET 20`
+	clauses []string
+}
+
+// buildPagination builds the LIMIT / OFFSET of the dialect, see dialect.PaginationStyle.
+//
+// The TOP clause is used only if allowTop, which is false for the combined results
+// of set operations, since TOP applies to the first operand only.
+func buildPagination(caps dialect.Capabilities, ordered, allowTop bool, limit, offset int64) (*pagination, error) {
+	p := &pagination{}
+	switch caps.Pagination {
+	case dialect.PaginationTop:
+		if offset == 0 && allowTop {
+			if limit > 0 {
+				p.top = fmt.Sprintf(`TOP %d`, limit)
+			}
+			return p, nil
+		}
+		if limit == 0 && offset == 0 {
+			return p, nil
+		}
+		if !ordered {
+			return nil, fmt.Errorf("limit / offset: ORDER BY is required for OFFSET / FETCH")
+		}
+		p.clauses = offsetFetch(limit, offset, true)
+	case dialect.PaginationOffsetFetch:
+		p.clauses = offsetFetch(limit, offset, false)
+	default:
+		if limit > 0 {
+			p.clauses = append(p.clauses, fmt.Sprintf(`LIMIT %d`, limit))
+		}
+		if offset > 0 {
+			p.clauses = append(p.clauses, fmt.Sprintf(`OFFSET %d`, offset))
+		}
+	}
+	return p, nil
+}
+
+// offsetFetch builds `OFFSET m ROWS FETCH NEXT n ROWS ONLY`, in which
+// the OFFSET is required by FETCH if requireOffset, e.g. SQLServer.
+func offsetFetch(limit, offset int64, requireOffset bool) []string {
+	var clauses []string
+	if offset > 0 || (limit > 0 && requireOffset) {
+		clauses = append(clauses, fmt.Sprintf(`OFFSET %d ROWS`, offset))
+	}
+	if limit > 0 {
+		clauses = append(clauses, fmt.Sprintf(`FETCH NEXT %d ROWS ONLY`, limit))
+	}
+	return clauses
+}
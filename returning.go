@@ -0,0 +1,35 @@
+package sqlb
+
+import (
+	"fmt"
+
+	"github.com/qjebbs/go-sqlf/v4"
+)
+
+// returningColumns returns the builders of the returning columns,
+// in which "*" is kept as is.
+func returningColumns(columns []string) []sqlf.Builder {
+	r := make([]sqlf.Builder, 0, len(columns))
+	for _, c := range columns {
+		if c == "*" {
+			r = append(r, sqlf.F("*"))
+			continue
+		}
+		r = append(r, sqlf.Identifier(c))
+	}
+	return r
+}
+
+// buildOutput builds the OUTPUT clause of SQLServer, in which the columns
+// are prefixed with the pseudo table, e.g. `OUTPUT DELETED.[id]`.
+func buildOutput(ctx Context, pseudo string, columns []sqlf.Builder) (string, error) {
+	prefixed := make([]sqlf.Builder, 0, len(columns))
+	for _, c := range columns {
+		prefixed = append(prefixed, sqlf.F(pseudo+".?", c))
+	}
+	output, err := sqlf.F("OUTPUT ?", sqlf.Join(prefixed, ", ")).BuildTo(ctx)
+	if err != nil {
+		return "", fmt.Errorf("build output clause: %w", err)
+	}
+	return output, nil
+}
@@ -1,6 +1,9 @@
 package sqlb
 
 import (
+	"reflect"
+	"time"
+
 	"github.com/qjebbs/go-sqlb/internal/util"
 	"github.com/qjebbs/go-sqlf/v4"
 )
@@ -17,14 +20,18 @@ type SelectBuilder struct {
 
 	selects  *clauseList // select columns and keep values in scanning.
 	where    *clauseList
-	order    *clauseList // order by columns, joined with comma.
-	groupbys *clauseList // group by columns, joined with comma.
-	having   *clauseList // having conditions, joined with AND.
-	distinct bool        // select distinct
-	limit    int64       // limit count
-	offset   int64       // offset count
-	unions   *clauseList // union queries
-	errors   []error     // errors during building
+	order    *clauseList     // order by columns, joined with comma.
+	groupbys *clauseList     // group by columns, joined with comma.
+	having   *clauseList     // having conditions, joined with AND.
+	windows  *clauseList     // named windows, joined with comma.
+	distinct bool            // select distinct
+	limit    int64           // limit count
+	offset   int64           // offset count
+	unions   *clauseList     // set operations, e.g. UNION, INTERSECT
+	lock     rowLock         // row locking options
+	hints    []statementHint // statement level optimizer hints
+	timeout  time.Duration   // execution time limit
+	errors   []error         // errors during building
 
 	debugger
 
@@ -40,6 +47,7 @@ func NewSelectBuilder() *SelectBuilder {
 		order:    newPrefixedList("ORDER BY", ", "),
 		groupbys: newPrefixedList("GROUP BY", ", "),
 		having:   newPrefixedList("HAVING", " AND "),
+		windows:  newPrefixedList("WINDOW", ", "),
 		selects:  newPrefixedList("SELECT", ", "),
 		where:    newPrefixedList("WHERE", " AND "),
 		unions:   newPrefixedList("", " "),
@@ -76,6 +84,19 @@ func (b *SelectBuilder) SetSelect(columns ...sqlf.Builder) {
 	b.selects.Replace(columns)
 }
 
+// SelectCoalesced appends a column to the SELECT clause, which is coalesced
+// to the zero value of goType if it's NULL, so that it can be scanned into
+// the non-nullable goType, see dialect.Dialect.NullCoalesce.
+//
+//	foo := sqlb.NewTable("foo")
+//	b.SelectCoalesced(foo.Column("bar"), reflect.TypeOf(int64(0)))
+//	// PostgreSQL: SELECT COALESCE("f"."bar", 0)
+func (b *SelectBuilder) SelectCoalesced(column sqlf.Builder, goType reflect.Type) *SelectBuilder {
+	b.resetDepTablesCache()
+	b.selects.Append(&coalescedColumn{column: column, goType: goType})
+	return b
+}
+
 // Limit set the limit.
 func (b *SelectBuilder) Limit(limit int64) *SelectBuilder {
 	b.SetLimit(limit)
@@ -113,22 +134,39 @@ func (b *SelectBuilder) OrderBy(order ...sqlf.Builder) *SelectBuilder {
 //
 //	foo := sqlb.NewTable("foo")
 //	b.GroupBy(foo.Column("bar"))
+//
+// To group by multiple grouping sets, use Rollup, Cube and GroupingSets.
 func (b *SelectBuilder) GroupBy(columns ...sqlf.Builder) *SelectBuilder {
 	b.resetDepTablesCache()
 	b.groupbys.Append(columns...)
 	return b
 }
 
+// Window defines a named window in the WINDOW clause,
+// which is referenced by WindowBuilder.Window.
+//
+//	o := sqlb.NewTable("orders", "o")
+//	b.Window("w", sqlb.NewWindow().PartitionBy(o.Column("user_id")).OrderBy(o.Column("id"))).
+//		Select(
+//			sqlb.Over(sqlf.F("ROW_NUMBER()")).Window("w"),
+//			sqlb.Over(sqlf.F("SUM(?)", o.Column("amount"))).Window("w"),
+//		)
+func (b *SelectBuilder) Window(name string, spec *WindowBuilder) *SelectBuilder {
+	b.resetDepTablesCache()
+	b.windows.Append(&namedWindow{name: name, spec: spec})
+	return b
+}
+
 // Union unions other builders.
 //
+// The ORDER BY / LIMIT / OFFSET of b and the builders are scoped to themselves,
+// which are parenthesized as required. To sort or limit the combined result,
+// use CompoundBuilder instead.
+//
 // !!! Make sure the all table references within the builders are built from sqlb.Table
 // to have their dependencies tracked.
 func (b *SelectBuilder) Union(builders ...sqlf.Builder) *SelectBuilder {
-	b.resetDepTablesCache()
-	b.unions.Append(util.Map(builders, func(b sqlf.Builder) sqlf.Builder {
-		return sqlf.Prefix("UNION", b)
-	})...)
-	return b
+	return b.setOperation("UNION", false, builders)
 }
 
 // UnionAll unions other builders with 'UNION ALL'.
@@ -136,9 +174,47 @@ func (b *SelectBuilder) Union(builders ...sqlf.Builder) *SelectBuilder {
 // !!! Make sure the all table references within the builders are built from sqlb.Table
 // to have their dependencies tracked.
 func (b *SelectBuilder) UnionAll(builders ...sqlf.Builder) *SelectBuilder {
+	return b.setOperation("UNION", true, builders)
+}
+
+// Intersect intersects other builders.
+//
+// !!! Make sure the all table references within the builders are built from sqlb.Table
+// to have their dependencies tracked.
+func (b *SelectBuilder) Intersect(builders ...sqlf.Builder) *SelectBuilder {
+	return b.setOperation("INTERSECT", false, builders)
+}
+
+// IntersectAll intersects other builders with 'INTERSECT ALL',
+// which is not supported by SQLite, SQLServer and Oracle.
+//
+// !!! Make sure the all table references within the builders are built from sqlb.Table
+// to have their dependencies tracked.
+func (b *SelectBuilder) IntersectAll(builders ...sqlf.Builder) *SelectBuilder {
+	return b.setOperation("INTERSECT", true, builders)
+}
+
+// Except excepts other builders, which is built as 'MINUS' for Oracle.
+//
+// !!! Make sure the all table references within the builders are built from sqlb.Table
+// to have their dependencies tracked.
+func (b *SelectBuilder) Except(builders ...sqlf.Builder) *SelectBuilder {
+	return b.setOperation("EXCEPT", false, builders)
+}
+
+// ExceptAll excepts other builders with 'EXCEPT ALL',
+// which is not supported by SQLite, SQLServer and Oracle.
+//
+// !!! Make sure the all table references within the builders are built from sqlb.Table
+// to have their dependencies tracked.
+func (b *SelectBuilder) ExceptAll(builders ...sqlf.Builder) *SelectBuilder {
+	return b.setOperation("EXCEPT", true, builders)
+}
+
+func (b *SelectBuilder) setOperation(op string, all bool, builders []sqlf.Builder) *SelectBuilder {
 	b.resetDepTablesCache()
 	b.unions.Append(util.Map(builders, func(b sqlf.Builder) sqlf.Builder {
-		return sqlf.Prefix("UNION ALL", b)
+		return &setOperation{op: op, all: all, builder: b}
 	})...)
 	return b
 }
@@ -48,6 +48,8 @@ func (b *SelectBuilder) buildInternal(ctx Context) (string, error) {
 	}
 	built := make([]string, 0)
 
+	nested := isNestedStatement(ctx)
+	ctx = contextWithNestedStatement(ctx)
 	ctx, pruning := decideContextPruning(ctx, b.pruning)
 
 	var err error
@@ -69,23 +71,56 @@ func (b *SelectBuilder) buildInternal(ctx Context) (string, error) {
 			return "", nil
 		}
 	}
-	with, err := b.ctes.BuildRequired(ctx, myDeps.cteDeps)
+	var hints []statementHint
+	if !nested {
+		// statement level hints and the timeout apply to the top-level statement only
+		timeoutHint, err := timeoutHint(ctx, b.timeout)
+		if err != nil {
+			return "", err
+		}
+		hints = b.hints
+		if timeoutHint != "" {
+			hints = append(hints[:len(hints):len(hints)], statementHint{hint: timeoutHint})
+		}
+	}
+	fromMeta := &fromBuilderMeta{
+		DebugName:  b.name,
+		Distinct:   b.distinct,
+		HasGroupBy: !b.groupbys.Empty(),
+		LockHints:  b.lock.tableHints(ctx.Dialect().Capabilities()),
+		LockTables: b.lock.of,
+	}
+	hint, leadingHint, hintOption := buildHints(ctx, hints, b.from.IndexHintComments(ctx, fromMeta, myDeps.queryDeps))
+	if leadingHint != "" {
+		built = append(built, leadingHint)
+	}
+	with, err := b.ctes.BuildRequired(ctx, myDeps.cteDeps, nested)
 	if err != nil {
 		return "", err
 	}
 	if with != "" {
 		built = append(built, with)
 	}
-	sel, err := b.buildSelects(ctx)
+	// the statement head, which is not scoped in the set operations
+	head := built
+	built = make([]string, 0)
+	page, err := buildPagination(ctx.Dialect().Capabilities(), !b.order.Empty(), true, b.limit, b.offset)
+	if err != nil {
+		return "", err
+	}
+	sel, err := b.buildSelects(ctx, hint, page.top)
 	if err != nil {
 		return "", err
 	}
 	built = append(built, sel)
-	from, err := b.from.BuildRequired(ctx, &fromBuilderMeta{
-		DebugName:  b.name,
-		Distinct:   b.distinct,
-		HasGroupBy: !b.groupbys.Empty(),
-	}, myDeps.queryDeps)
+	if b.lock.mode != lockNone && !b.unions.Empty() {
+		return "", fmt.Errorf("row locking: not allowed with set operations")
+	}
+	lock, err := b.lock.BuildTo(ctx)
+	if err != nil {
+		return "", err
+	}
+	from, err := b.from.BuildRequired(ctx, fromMeta, myDeps.queryDeps)
 	if err != nil {
 		return "", err
 	}
@@ -99,6 +134,9 @@ func (b *SelectBuilder) buildInternal(ctx Context) (string, error) {
 	if where != "" {
 		built = append(built, where)
 	}
+	if err := checkGroupingElements(ctx, b.groupbys.elements); err != nil {
+		return "", err
+	}
 	groupby, err := b.groupbys.BuildTo(ctx)
 	if err != nil {
 		return "", err
@@ -113,6 +151,13 @@ func (b *SelectBuilder) buildInternal(ctx Context) (string, error) {
 			built = append(built, having)
 		}
 	}
+	windows, err := b.windows.BuildTo(ctx)
+	if err != nil {
+		return "", err
+	}
+	if windows != "" {
+		built = append(built, windows)
+	}
 	order, err := b.order.BuildTo(ctx)
 	if err != nil {
 		return "", err
@@ -120,30 +165,46 @@ func (b *SelectBuilder) buildInternal(ctx Context) (string, error) {
 	if order != "" {
 		built = append(built, order)
 	}
-	if b.limit > 0 {
-		built = append(built, fmt.Sprintf(`LIMIT %d`, b.limit))
-	}
-	if b.offset > 0 {
-		built = append(built, fmt.Sprintf(`OFFSET %d`, b.offset))
+	built = append(built, page.clauses...)
+	if lock != "" {
+		built = append(built, lock)
 	}
-	query := strings.TrimSpace(strings.Join(built, " "))
+	query := strings.Join(built, " ")
 	if !b.unions.Empty() {
-		union, err := b.unions.BuildTo(ctx)
+		if b.hasScopedClauses() {
+			query = parenthesizeSetOperand(ctx, query)
+		}
+		query, err = buildSetOperations(ctx, query, b.unions.elements)
 		if err != nil {
 			return "", err
 		}
-		query = strings.TrimSpace(query + " " + union)
+	}
+	query = strings.TrimSpace(strings.Join(append(head, query), " "))
+	if hintOption != "" {
+		query += " " + hintOption
 	}
 	b.debugger.printIfDebug(ctx, query, ctx.Args())
 	return query, nil
 }
 
-func (b *SelectBuilder) buildSelects(ctx Context) (string, error) {
+// hasScopedClauses reports whether the query has ORDER BY / LIMIT / OFFSET,
+// which are scoped to itself in set operations.
+func (b *SelectBuilder) hasScopedClauses() bool {
+	return !b.order.Empty() || b.limit > 0 || b.offset > 0
+}
+
+func (b *SelectBuilder) buildSelects(ctx Context, hint, top string) (string, error) {
+	prefix := "SELECT"
+	if hint != "" {
+		prefix += " " + hint
+	}
 	if b.distinct {
-		b.selects.SetPrefix("SELECT DISTINCT")
-	} else {
-		b.selects.SetPrefix("SELECT")
+		prefix += " DISTINCT"
+	}
+	if top != "" {
+		prefix += " " + top
 	}
+	b.selects.SetPrefix(prefix)
 	sel, err := b.selects.BuildTo(ctx)
 	if err != nil {
 		return "", err
@@ -175,7 +236,9 @@ func (b *SelectBuilder) collectDependencies(ctx Context) (*selectBuilderDependen
 			b.order,
 			b.groupbys,
 			b.having,
+			b.windows,
 			b.unions,
+			&b.lock,
 		},
 		Distinct:   b.distinct,
 		HasGroupBy: !b.groupbys.Empty(),
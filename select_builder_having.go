@@ -2,7 +2,6 @@ package sqlb
 
 import (
 	"github.com/qjebbs/go-sqlf/v4"
-	"github.com/qjebbs/go-sqlf/v4/util"
 )
 
 // Having add a having condition.
@@ -90,23 +89,23 @@ func (b *SelectBuilder) HavingNotBetween(column sqlf.Builder, start, end any) *S
 }
 
 // HavingIn adds a having IN condition like `t.id IN (1,2,3)`
+//
+// Lists longer than the threshold are rendered with the LargeInListStrategy
+// of the dialect, see ContextWithInListThreshold.
+// An empty list is built as an always-false predicate, see ContextWithStrictInList.
 func (b *SelectBuilder) HavingIn(column sqlf.Builder, list any) *SelectBuilder {
 	return b.Having(
-		sqlf.F(
-			"? IN (?)",
-			column,
-			sqlf.JoinArgs(util.FlattenArgs(list), ", "),
-		),
+		newInPredicate(column, list, false),
 	)
 }
 
 // HavingNotIn adds a having NOT IN condition like `t.id NOT IN (1,2,3)`
+//
+// Lists longer than the threshold are rendered with the LargeInListStrategy
+// of the dialect, see ContextWithInListThreshold.
+// An empty list is built as an always-true predicate, see ContextWithStrictInList.
 func (b *SelectBuilder) HavingNotIn(column sqlf.Builder, list any) *SelectBuilder {
 	return b.Having(
-		sqlf.F(
-			"? NOT IN (?)",
-			column,
-			sqlf.JoinArgs(util.FlattenArgs(list), ", "),
-		),
+		newInPredicate(column, list, true),
 	)
 }
@@ -0,0 +1,100 @@
+package sqlb
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/qjebbs/go-sqlb/dialect"
+)
+
+// UseIndex hints the optimizer to use one of the indexes for the table,
+// which is added by From() or joins, e.g.:
+//
+//	MySQL:     FROM `foo` AS `f` USE INDEX (`idx_a`)
+//	SQLServer: FROM [foo] AS [f] WITH (INDEX([idx_a]))
+//	Oracle:    SELECT /*+ INDEX("f" "idx_a") */ ...
+//
+// The hint is dropped quietly by the dialects without index hints, e.g. PostgreSQL.
+func (b *SelectBuilder) UseIndex(t Table, indexes ...string) *SelectBuilder {
+	b.from.IndexHint(t, indexHintUse, indexes)
+	return b
+}
+
+// ForceIndex forces the optimizer to use one of the indexes for the table,
+// see UseIndex. On SQLite, a single index is forced with `INDEXED BY`.
+func (b *SelectBuilder) ForceIndex(t Table, indexes ...string) *SelectBuilder {
+	b.from.IndexHint(t, indexHintForce, indexes)
+	return b
+}
+
+// IgnoreIndex hints the optimizer not to use the indexes for the table, e.g.:
+//
+//	MySQL:  FROM `foo` AS `f` IGNORE INDEX (`idx_a`)
+//	Oracle: SELECT /*+ NO_INDEX("f" "idx_a") */ ...
+//
+// The hint is dropped quietly by the other dialects.
+func (b *SelectBuilder) IgnoreIndex(t Table, indexes ...string) *SelectBuilder {
+	b.from.IndexHint(t, indexHintIgnore, indexes)
+	return b
+}
+
+// Hint adds a statement level optimizer hint, which is rendered only for the
+// dialects of the same types as the given ones, or all dialects if none given.
+// It's placed according to the dialect, e.g.:
+//
+//	b.Hint("PARALLEL(4)", dialect.Oracle{})   // SELECT /*+ PARALLEL(4) */ ...
+//	b.Hint("RECOMPILE", dialect.SQLServer{})  // SELECT ... OPTION (RECOMPILE)
+//	b.Hint("SeqScan(f)", dialect.PostgreSQL{}) // /*+ SeqScan(f) */ SELECT ...
+//
+// The hint is dropped quietly by the dialects without optimizer hints, e.g. SQLite,
+// and when the builder is nested in another statement, e.g. as a subquery.
+func (b *SelectBuilder) Hint(hint string, dialects ...dialect.Dialect) *SelectBuilder {
+	b.hints = append(b.hints, statementHint{
+		hint:     hint,
+		dialects: dialects,
+	})
+	return b
+}
+
+// statementHint is a statement level optimizer hint for the dialects.
+type statementHint struct {
+	hint     string
+	dialects []dialect.Dialect
+}
+
+func (h statementHint) appliesTo(d dialect.Dialect) bool {
+	if len(h.dialects) == 0 {
+		return true
+	}
+	for _, target := range h.dialects {
+		if reflect.TypeOf(target) == reflect.TypeOf(d) {
+			return true
+		}
+	}
+	return false
+}
+
+// buildHints returns the statement hints of the dialect, in which the hint
+// comments are like `/*+ a b */` and the hint option is like `OPTION (a, b)`.
+func buildHints(ctx Context, hints []statementHint, indexHints []string) (comment, leading, option string) {
+	d := ctx.Dialect()
+	applied := make([]string, 0, len(hints)+len(indexHints))
+	applied = append(applied, indexHints...)
+	for _, h := range hints {
+		if h.appliesTo(d) {
+			applied = append(applied, h.hint)
+		}
+	}
+	if len(applied) == 0 {
+		return "", "", ""
+	}
+	switch d.Capabilities().StatementHints {
+	case dialect.StatementHintComment:
+		comment = "/*+ " + strings.Join(applied, " ") + " */"
+	case dialect.StatementHintLeadingComment:
+		leading = "/*+ " + strings.Join(applied, " ") + " */"
+	case dialect.StatementHintOption:
+		option = "OPTION (" + strings.Join(applied, ", ") + ")"
+	}
+	return comment, leading, option
+}
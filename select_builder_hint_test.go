@@ -0,0 +1,224 @@
+package sqlb_test
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/qjebbs/go-sqlb"
+	"github.com/qjebbs/go-sqlb/dialect"
+	"github.com/qjebbs/go-sqlf/v4"
+)
+
+func TestSelectHintsMySQL(t *testing.T) {
+	var (
+		foo = sqlb.NewTable("foo", "f")
+		bar = sqlb.NewTable("bar", "b")
+	)
+	q := sqlb.NewSelectBuilder().
+		Select(foo.Column("id")).
+		From(foo).
+		InnerJoin(bar, sqlf.F("? = ?", bar.Column("foo_id"), foo.Column("id"))).
+		Where(sqlf.F("? > ?", foo.Column("id"), 1)).
+		UseIndex(foo, "idx_foo_a").
+		ForceIndex(bar, "idx_bar_foo_id").
+		Hint("PARALLEL(4)", dialect.Oracle{}).
+		Hint("RECOMPILE", dialect.SQLServer{}).
+		Hint("SeqScan(f)", dialect.PostgreSQL{}).
+		IgnoreIndex(foo, "idx_foo_b")
+	ctx := sqlb.NewContext(context.Background(), dialect.MySQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := "SELECT `f`.`id` FROM `foo` AS `f` USE INDEX (`idx_foo_a`) IGNORE INDEX (`idx_foo_b`) INNER JOIN `bar` AS `b` FORCE INDEX (`idx_bar_foo_id`) ON `b`.`foo_id` = `f`.`id` WHERE `f`.`id` > ?"
+	wantArgs := []any{1}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectHintsOracle(t *testing.T) {
+	var (
+		foo = sqlb.NewTable("foo", "f")
+		bar = sqlb.NewTable("bar", "b")
+	)
+	q := sqlb.NewSelectBuilder().
+		Select(foo.Column("id")).
+		From(foo).
+		InnerJoin(bar, sqlf.F("? = ?", bar.Column("foo_id"), foo.Column("id"))).
+		Where(sqlf.F("? > ?", foo.Column("id"), 1)).
+		UseIndex(foo, "idx_foo_a").
+		ForceIndex(bar, "idx_bar_foo_id").
+		Hint("PARALLEL(4)", dialect.Oracle{}).
+		Hint("RECOMPILE", dialect.SQLServer{}).
+		Hint("SeqScan(f)", dialect.PostgreSQL{}).
+		Distinct()
+	ctx := sqlb.NewContext(context.Background(), dialect.Oracle{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT /*+ INDEX("f" "idx_foo_a") INDEX("b" "idx_bar_foo_id") PARALLEL(4) */ DISTINCT "f"."id" FROM "foo" AS "f" INNER JOIN "bar" AS "b" ON "b"."foo_id" = "f"."id" WHERE "f"."id" > :1`
+	wantArgs := []any{1}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectHintsSQLServer(t *testing.T) {
+	var (
+		foo = sqlb.NewTable("foo", "f")
+		bar = sqlb.NewTable("bar", "b")
+	)
+	q := sqlb.NewSelectBuilder().
+		Select(foo.Column("id")).
+		From(foo).
+		InnerJoin(bar, sqlf.F("? = ?", bar.Column("foo_id"), foo.Column("id"))).
+		Where(sqlf.F("? > ?", foo.Column("id"), 1)).
+		UseIndex(foo, "idx_foo_a").
+		ForceIndex(bar, "idx_bar_foo_id").
+		Hint("PARALLEL(4)", dialect.Oracle{}).
+		Hint("RECOMPILE", dialect.SQLServer{}).
+		Hint("SeqScan(f)", dialect.PostgreSQL{}).
+		ForUpdate()
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLServer{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT [f].[id] FROM [foo] AS [f] WITH (INDEX([idx_foo_a]), UPDLOCK, ROWLOCK) INNER JOIN [bar] AS [b] WITH (INDEX([idx_bar_foo_id]), UPDLOCK, ROWLOCK) ON [b].[foo_id] = [f].[id] WHERE [f].[id] > @p1 OPTION (RECOMPILE)`
+	wantArgs := []any{sql.Named("p1", 1)}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectHintsSQLite(t *testing.T) {
+	var (
+		foo = sqlb.NewTable("foo", "f")
+		bar = sqlb.NewTable("bar", "b")
+	)
+	q := sqlb.NewSelectBuilder().
+		Select(foo.Column("id")).
+		From(foo).
+		InnerJoin(bar, sqlf.F("? = ?", bar.Column("foo_id"), foo.Column("id"))).
+		Where(sqlf.F("? > ?", foo.Column("id"), 1)).
+		UseIndex(foo, "idx_foo_a").
+		ForceIndex(bar, "idx_bar_foo_id").
+		Hint("PARALLEL(4)", dialect.Oracle{}).
+		Hint("RECOMPILE", dialect.SQLServer{}).
+		Hint("SeqScan(f)", dialect.PostgreSQL{})
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLite{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT "f"."id" FROM "foo" AS "f" INNER JOIN "bar" AS "b" INDEXED BY "idx_bar_foo_id" ON "b"."foo_id" = "f"."id" WHERE "f"."id" > ?`
+	wantArgs := []any{1}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectHintsPostgreSQL(t *testing.T) {
+	var (
+		foo = sqlb.NewTable("foo", "f")
+		bar = sqlb.NewTable("bar", "b")
+	)
+	q := sqlb.NewSelectBuilder().
+		Select(foo.Column("id")).
+		From(foo).
+		InnerJoin(bar, sqlf.F("? = ?", bar.Column("foo_id"), foo.Column("id"))).
+		Where(sqlf.F("? > ?", foo.Column("id"), 1)).
+		UseIndex(foo, "idx_foo_a").
+		ForceIndex(bar, "idx_bar_foo_id").
+		Hint("PARALLEL(4)", dialect.Oracle{}).
+		Hint("RECOMPILE", dialect.SQLServer{}).
+		Hint("SeqScan(f)", dialect.PostgreSQL{})
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `/*+ SeqScan(f) */ SELECT "f"."id" FROM "foo" AS "f" INNER JOIN "bar" AS "b" ON "b"."foo_id" = "f"."id" WHERE "f"."id" > $1`
+	wantArgs := []any{1}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectHintsNested(t *testing.T) {
+	var (
+		foo = sqlb.NewTable("foo", "f")
+		bar = sqlb.NewTable("bar", "b")
+	)
+	sub := sqlb.NewSelectBuilder().
+		Select(sqlf.F("1")).
+		From(bar).
+		Where(sqlf.F("? = ?", bar.Column("foo_id"), foo.Column("id"))).
+		Hint("MAXDOP 1")
+	q := sqlb.NewSelectBuilder().
+		Select(foo.Column("id")).
+		From(foo).
+		Where(sqlf.F("EXISTS (?)", sub)).
+		Hint("RECOMPILE")
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLServer{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT [f].[id] FROM [foo] AS [f] WHERE EXISTS (SELECT 1 FROM [bar] AS [b] WHERE [b].[foo_id] = [f].[id]) OPTION (RECOMPILE)`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectIndexHintsEliminated(t *testing.T) {
+	var (
+		foo = sqlb.NewTable("foo", "f")
+		bar = sqlb.NewTable("bar", "b")
+	)
+	q := sqlb.NewSelectBuilder().
+		EnableElimination().
+		Distinct().
+		Select(foo.Column("id")).
+		From(foo).
+		LeftJoin(bar, sqlf.F("? = ?", bar.Column("foo_id"), foo.Column("id"))).
+		Where(sqlf.F("? > ?", foo.Column("id"), 1)).
+		UseIndex(foo, "idx_foo_a").
+		UseIndex(bar, "idx_bar_foo_id")
+	ctx := sqlb.NewContext(context.Background(), dialect.Oracle{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT /*+ INDEX("f" "idx_foo_a") */ DISTINCT "f"."id" FROM "foo" AS "f" WHERE "f"."id" > :1`
+	wantArgs := []any{1}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
@@ -1,6 +1,8 @@
 package sqlb
 
 import (
+	"fmt"
+
 	"github.com/qjebbs/go-sqlf/v4"
 )
 
@@ -60,14 +62,178 @@ func (b *SelectBuilder) CrossJoin(t Table) *SelectBuilder {
 	return b
 }
 
+// JoinKind is the kind of join for JoinSubquery.
+type JoinKind int
+
+// Join kinds.
+const (
+	JoinInner JoinKind = iota
+	// JoinLeft is eliminated like LeftJoin.
+	JoinLeft
+	// JoinLeftOptional is eliminated like LeftJoinOptional.
+	JoinLeftOptional
+	JoinRight
+	JoinFull
+	JoinCross
+)
+
+// joinOptions returns the JOIN keywords and the elimination options of the kind.
+func (k JoinKind) joinOptions() (joinStr string, optional, forceEliminate bool, err error) {
+	switch k {
+	case JoinInner:
+		return "INNER JOIN", false, false, nil
+	case JoinLeft:
+		return "LEFT JOIN", true, false, nil
+	case JoinLeftOptional:
+		return "LEFT JOIN", true, true, nil
+	case JoinRight:
+		return "RIGHT JOIN", false, false, nil
+	case JoinFull:
+		return "FULL JOIN", false, false, nil
+	case JoinCross:
+		return "CROSS JOIN", false, false, nil
+	}
+	return "", false, false, fmt.Errorf("unknown join kind: %d", k)
+}
+
+// FromSubquery set the from derived table, e.g.:
+//
+//	s := sqlb.NewTable("s")
+//	b.FromSubquery(sub, s).Select(s.Column("id"))
+//	// SELECT "s"."id" FROM (SELECT ...) AS "s"
+//
+// The derived table is aliased by the applied name of alias,
+// and its columns should be referenced with alias.
+func (b *SelectBuilder) FromSubquery(builder sqlf.Builder, alias Table) *SelectBuilder {
+	b.resetDepTablesCache()
+	b.from.FromSubquery(builder, alias)
+	return b
+}
+
+// JoinSubquery append / replace a derived table join, e.g.:
+//
+//	s := sqlb.NewTable("s")
+//	b.JoinSubquery(sqlb.JoinLeft, sub, s, sqlf.F("? = ?", s.Column("foo_id"), foo.Column("id")))
+//	// LEFT JOIN (SELECT ...) AS "s" ON "s"."foo_id" = "f"."id"
+//
+// The derived table is aliased by the applied name of alias, and it's
+// eliminated according to the kind, see LeftJoin and LeftJoinOptional.
+// The tables of outer scope referenced in the subquery are reported to
+// the outer builders.
+func (b *SelectBuilder) JoinSubquery(kind JoinKind, builder sqlf.Builder, alias Table, on *sqlf.Fragment) *SelectBuilder {
+	b.resetDepTablesCache()
+	joinStr, optional, forceEliminate, err := kind.joinOptions()
+	if err != nil {
+		b.from.pushError(err)
+		return b
+	}
+	b.from.JoinSubquery(joinStr, builder, alias, on, optional, forceEliminate)
+	return b
+}
+
+// FromFunc set the from table-valued function, e.g.:
+//
+//	g := sqlb.NewTableFunc("generate_series", "g", 1, 10).WithColumns("n")
+//	b.Select(g.Column("n")).FromFunc(g)
+//	// SELECT "g"."n" FROM generate_series($1, $2) AS "g" (n)
+func (b *SelectBuilder) FromFunc(f TableFunc) *SelectBuilder {
+	b.resetDepTablesCache()
+	b.from.FromFunc(f)
+	return b
+}
+
+// JoinFunc append / replace a table-valued function join, e.g.:
+//
+//	e := sqlb.NewTableFunc("json_each", "e", foo.Column("tags"))
+//	b.JoinFunc(sqlb.JoinCross, e, nil)
+//	// CROSS JOIN json_each("f"."tags") AS "e"
+//
+// The arguments can reference the preceding tables, in which case
+// JoinCross is rendered as CROSS APPLY for SQLServer and Oracle.
+// It's eliminated according to the kind, see LeftJoin and LeftJoinOptional.
+func (b *SelectBuilder) JoinFunc(kind JoinKind, f TableFunc, on *sqlf.Fragment) *SelectBuilder {
+	b.resetDepTablesCache()
+	joinStr, optional, forceEliminate, err := kind.joinOptions()
+	if err != nil {
+		b.from.pushError(err)
+		return b
+	}
+	b.from.JoinFunc(joinStr, f, on, optional, forceEliminate)
+	return b
+}
+
+// LateralJoin append / replace a lateral join of the derived table, whose
+// subquery can reference the preceding tables, e.g.:
+//
+//	PostgreSQL: CROSS JOIN LATERAL (SELECT ...) AS "s"
+//	SQLServer:  CROSS APPLY (SELECT ...) AS [s]
+//
+// The derived table is aliased by the applied name of alias.
+func (b *SelectBuilder) LateralJoin(builder sqlf.Builder, alias Table) *SelectBuilder {
+	b.resetDepTablesCache()
+	b.from.JoinLateral("CROSS JOIN", builder, alias, false, false)
+	return b
+}
+
+// LeftLateralJoin append / replace a left lateral join of the derived table,
+// whose subquery can reference the preceding tables, e.g.:
+//
+//	PostgreSQL: LEFT JOIN LATERAL (SELECT ... LIMIT 1) AS "s" ON TRUE
+//	SQLServer:  OUTER APPLY (SELECT TOP 1 ...) AS [s]
+//
+// It's eliminated in the same way as LeftJoin.
+func (b *SelectBuilder) LeftLateralJoin(builder sqlf.Builder, alias Table) *SelectBuilder {
+	b.resetDepTablesCache()
+	b.from.JoinLateral("LEFT JOIN", builder, alias, true, false)
+	return b
+}
+
+// LeftLateralJoinOptional append / replace a left lateral join of the derived table,
+// which is eliminated in the same way as LeftJoinOptional, e.g. it's safe for
+// the top-1-per-row subqueries.
+func (b *SelectBuilder) LeftLateralJoinOptional(builder sqlf.Builder, alias Table) *SelectBuilder {
+	b.resetDepTablesCache()
+	b.from.JoinLateral("LEFT JOIN", builder, alias, true, true)
+	return b
+}
+
 // With adds a builder as common table expression.
 //
 // The CTE will be automatically eliminated if all the conditions below are met:
 //   - Pruning is enabled by `b.EnableElimination()` or parent builders
 //   - The table is not referenced anywhere in the query
-func (b *SelectBuilder) With(name Table, builder sqlf.Builder) *SelectBuilder {
+//
+// The optional CTEOptions declares the column names and the materialization hint, e.g.:
+//
+//	b.With(t, builder, sqlb.CTEOptions{Columns: []string{"a", "b"}, Materialized: sqlb.CTEMaterialized})
+//	// "t" ("a", "b") AS MATERIALIZED (...)
+func (b *SelectBuilder) With(name Table, builder sqlf.Builder, opts ...CTEOptions) *SelectBuilder {
+	b.resetDepTablesCache()
+	b.ctes.With(name, builder, opts...)
+	return b
+}
+
+// WithRecursive adds a recursive common table expression, built as
+// `name (columns) AS (anchor UNION ALL recursive)`, where the recursive member
+// references the CTE itself, e.g. walking a tree:
+//
+//	tree := sqlb.NewTable("tree")
+//	nodes := sqlb.NewTable("nodes", "n")
+//	anchor := sqlb.NewSelectBuilder().
+//		Select(nodes.Columns("id", "parent_id")...).
+//		From(nodes).
+//		WhereEquals(nodes.Column("id"), 1)
+//	recursive := sqlb.NewSelectBuilder().
+//		Select(nodes.Columns("id", "parent_id")...).
+//		From(nodes).
+//		InnerJoin(tree, sqlf.F("? = ?", nodes.Column("parent_id"), tree.Column("id")))
+//	b.WithRecursive(tree, []string{"id", "parent_id"}, anchor, recursive)
+//
+// It's built with `WITH RECURSIVE` for the dialects requiring the keyword.
+// The columns are required by the dialects requiring the column list, e.g. Oracle.
+func (b *SelectBuilder) WithRecursive(name Table, columns []string, anchor, recursive sqlf.Builder) *SelectBuilder {
 	b.resetDepTablesCache()
-	b.ctes.With(name, builder)
+	b.ctes.WithRecursive(name, columns, anchor, recursive)
 	return b
 }
 
@@ -0,0 +1,167 @@
+package sqlb
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/qjebbs/go-sqlb/dialect"
+	"github.com/qjebbs/go-sqlb/internal/util"
+	"github.com/qjebbs/go-sqlf/v4"
+)
+
+// ForUpdate locks the selected rows for update, e.g.:
+//
+//	PostgreSQL: SELECT ... FOR UPDATE
+//	SQLServer:  SELECT ... FROM t WITH (UPDLOCK, ROWLOCK)
+//
+// The build fails if the dialect cannot lock rows (e.g. SQLite),
+// or the query has UNION.
+func (b *SelectBuilder) ForUpdate() *SelectBuilder {
+	b.lock.mode = lockForUpdate
+	return b
+}
+
+// ForShare locks the selected rows in shared mode, e.g.:
+//
+//	PostgreSQL: SELECT ... FOR SHARE
+//	MySQL:      SELECT ... LOCK IN SHARE MODE
+//	SQLServer:  SELECT ... FROM t WITH (REPEATABLEREAD, ROWLOCK)
+func (b *SelectBuilder) ForShare() *SelectBuilder {
+	b.lock.mode = lockForShare
+	return b
+}
+
+// SkipLocked skips the rows locked by other transactions instead of waiting,
+// which works with ForUpdate / ForShare, e.g.:
+//
+//	PostgreSQL: SELECT ... FOR UPDATE SKIP LOCKED
+//	SQLServer:  SELECT ... FROM t WITH (UPDLOCK, READPAST, ROWLOCK)
+func (b *SelectBuilder) SkipLocked() *SelectBuilder {
+	b.lock.wait = lockSkipLocked
+	return b
+}
+
+// NoWait fails immediately if any selected row is locked by other transactions,
+// which works with ForUpdate / ForShare, e.g.:
+//
+//	PostgreSQL: SELECT ... FOR UPDATE NOWAIT
+//	SQLServer:  SELECT ... FROM t WITH (UPDLOCK, ROWLOCK, NOWAIT)
+func (b *SelectBuilder) NoWait() *SelectBuilder {
+	b.lock.wait = lockNoWait
+	return b
+}
+
+// Of restricts the row locking to the rows of tables, e.g.:
+//
+//	PostgreSQL: SELECT ... FOR UPDATE OF "j"
+//	SQLServer:  SELECT ... FROM [jobs] AS [j] WITH (UPDLOCK, ROWLOCK) JOIN ...
+func (b *SelectBuilder) Of(tables ...Table) *SelectBuilder {
+	b.resetDepTablesCache()
+	b.lock.of = append(b.lock.of, tables...)
+	return b
+}
+
+type lockMode int
+
+const (
+	lockNone lockMode = iota
+	lockForUpdate
+	lockForShare
+)
+
+type lockWait int
+
+const (
+	lockWaitDefault lockWait = iota
+	lockSkipLocked
+	lockNoWait
+)
+
+var _ sqlf.Builder = (*rowLock)(nil)
+
+// rowLock is the row locking options of SelectBuilder.
+type rowLock struct {
+	mode lockMode
+	wait lockWait
+	of   []Table
+}
+
+// BuildTo implements sqlf.Builder, which builds the trailing locking clause,
+// or nothing if the dialect locks rows with table hints.
+func (l *rowLock) BuildTo(ctx sqlf.Context) (string, error) {
+	if l.mode == lockNone {
+		if l.wait != lockWaitDefault || len(l.of) > 0 {
+			return "", errors.New("row locking: SkipLocked / NoWait / Of requires ForUpdate or ForShare")
+		}
+		return "", nil
+	}
+	uCtx, err := contextUpgrade(ctx)
+	if err != nil {
+		return "", err
+	}
+	d := uCtx.Dialect()
+	caps := d.Capabilities()
+	switch {
+	case caps.RowLocking == dialect.RowLockingUnsupported:
+		return "", fmt.Errorf("row locking: not supported by %T", d)
+	case l.mode == lockForShare && !caps.SupportsForShare:
+		return "", fmt.Errorf("row locking: ForShare is not supported by %T", d)
+	case l.wait == lockSkipLocked && !caps.SupportsSkipLocked:
+		return "", fmt.Errorf("row locking: SkipLocked is not supported by %T", d)
+	case l.wait == lockNoWait && !caps.SupportsNoWait:
+		return "", fmt.Errorf("row locking: NoWait is not supported by %T", d)
+	case len(l.of) > 0 && !caps.SupportsLockOf:
+		return "", fmt.Errorf("row locking: Of is not supported by %T", d)
+	}
+	of := sqlf.Join(util.Map(l.of, func(t Table) sqlf.Builder { return t }), ", ")
+	if caps.RowLocking == dialect.RowLockingTableHints {
+		// the hints are built with the tables, but the tables are
+		// still required by the locking.
+		if _, err := of.BuildTo(uCtx); err != nil {
+			return "", err
+		}
+		return "", nil
+	}
+	clause := "FOR UPDATE"
+	if l.mode == lockForShare {
+		clause = "FOR SHARE"
+		if _, ok := d.(dialect.MySQL); ok && l.wait == lockWaitDefault && len(l.of) == 0 {
+			// compatible with MySQL 5.7
+			clause = "LOCK IN SHARE MODE"
+		}
+	}
+	if len(l.of) > 0 {
+		tables, err := of.BuildTo(uCtx)
+		if err != nil {
+			return "", err
+		}
+		clause += " OF " + tables
+	}
+	switch l.wait {
+	case lockSkipLocked:
+		clause += " SKIP LOCKED"
+	case lockNoWait:
+		clause += " NOWAIT"
+	}
+	return clause, nil
+}
+
+// tableHints returns the table hints for row locking,
+// if the dialect locks rows with table hints.
+func (l *rowLock) tableHints(caps dialect.Capabilities) []string {
+	if l.mode == lockNone || caps.RowLocking != dialect.RowLockingTableHints {
+		return nil
+	}
+	hints := []string{"UPDLOCK"}
+	if l.mode == lockForShare {
+		hints = []string{"REPEATABLEREAD"}
+	}
+	if l.wait == lockSkipLocked {
+		hints = append(hints, "READPAST")
+	}
+	hints = append(hints, "ROWLOCK")
+	if l.wait == lockNoWait {
+		hints = append(hints, "NOWAIT")
+	}
+	return hints
+}
@@ -0,0 +1,206 @@
+package sqlb_test
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/qjebbs/go-sqlb"
+	"github.com/qjebbs/go-sqlb/dialect"
+	"github.com/qjebbs/go-sqlf/v4"
+)
+
+func TestSelectForUpdateSkipLockedOfPostgreSQL(t *testing.T) {
+	var (
+		jobs   = sqlb.NewTable("jobs", "j")
+		queues = sqlb.NewTable("queues", "q")
+	)
+	q := sqlb.NewSelectBuilder().
+		Select(jobs.Column("id")).
+		From(jobs).
+		InnerJoin(queues, sqlf.F("? = ?", queues.Column("id"), jobs.Column("queue_id"))).
+		WhereEquals(jobs.Column("status"), "ready").
+		OrderBy(jobs.Column("id")).
+		Limit(10).
+		ForUpdate().SkipLocked().Of(jobs)
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT "j"."id" FROM "jobs" AS "j" INNER JOIN "queues" AS "q" ON "q"."id" = "j"."queue_id" WHERE "j"."status" = $1 ORDER BY "j"."id" LIMIT 10 FOR UPDATE OF "j" SKIP LOCKED`
+	wantArgs := []any{"ready"}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectForShareMySQL(t *testing.T) {
+	var (
+		jobs   = sqlb.NewTable("jobs", "j")
+		queues = sqlb.NewTable("queues", "q")
+	)
+	q := sqlb.NewSelectBuilder().
+		Select(jobs.Column("id")).
+		From(jobs).
+		InnerJoin(queues, sqlf.F("? = ?", queues.Column("id"), jobs.Column("queue_id"))).
+		WhereEquals(jobs.Column("status"), "ready").
+		OrderBy(jobs.Column("id")).
+		Limit(10).
+		ForShare()
+	ctx := sqlb.NewContext(context.Background(), dialect.MySQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := "SELECT `j`.`id` FROM `jobs` AS `j` INNER JOIN `queues` AS `q` ON `q`.`id` = `j`.`queue_id` WHERE `j`.`status` = ? ORDER BY `j`.`id` LIMIT 10 LOCK IN SHARE MODE"
+	wantArgs := []any{"ready"}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectForShareNoWaitMySQL(t *testing.T) {
+	var (
+		jobs   = sqlb.NewTable("jobs", "j")
+		queues = sqlb.NewTable("queues", "q")
+	)
+	q := sqlb.NewSelectBuilder().
+		Select(jobs.Column("id")).
+		From(jobs).
+		InnerJoin(queues, sqlf.F("? = ?", queues.Column("id"), jobs.Column("queue_id"))).
+		WhereEquals(jobs.Column("status"), "ready").
+		OrderBy(jobs.Column("id")).
+		Limit(10).
+		ForShare().NoWait()
+	ctx := sqlb.NewContext(context.Background(), dialect.MySQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := "SELECT `j`.`id` FROM `jobs` AS `j` INNER JOIN `queues` AS `q` ON `q`.`id` = `j`.`queue_id` WHERE `j`.`status` = ? ORDER BY `j`.`id` LIMIT 10 FOR SHARE NOWAIT"
+	wantArgs := []any{"ready"}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectForUpdateSkipLockedOfSQLServer(t *testing.T) {
+	var (
+		jobs   = sqlb.NewTable("jobs", "j")
+		queues = sqlb.NewTable("queues", "q")
+	)
+	q := sqlb.NewSelectBuilder().
+		Select(jobs.Column("id")).
+		From(jobs).
+		InnerJoin(queues, sqlf.F("? = ?", queues.Column("id"), jobs.Column("queue_id"))).
+		WhereEquals(jobs.Column("status"), "ready").
+		OrderBy(jobs.Column("id")).
+		Limit(10).
+		ForUpdate().SkipLocked().Of(jobs)
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLServer{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT TOP 10 [j].[id] FROM [jobs] AS [j] WITH (UPDLOCK, READPAST, ROWLOCK) INNER JOIN [queues] AS [q] ON [q].[id] = [j].[queue_id] WHERE [j].[status] = @p1 ORDER BY [j].[id]`
+	wantArgs := []any{sql.Named("p1", "ready")}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectForUpdateSQLServer(t *testing.T) {
+	var (
+		jobs   = sqlb.NewTable("jobs", "j")
+		queues = sqlb.NewTable("queues", "q")
+	)
+	q := sqlb.NewSelectBuilder().
+		Select(jobs.Column("id")).
+		From(jobs).
+		InnerJoin(queues, sqlf.F("? = ?", queues.Column("id"), jobs.Column("queue_id"))).
+		WhereEquals(jobs.Column("status"), "ready").
+		OrderBy(jobs.Column("id")).
+		Limit(10).
+		ForUpdate()
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLServer{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT TOP 10 [j].[id] FROM [jobs] AS [j] WITH (UPDLOCK, ROWLOCK) INNER JOIN [queues] AS [q] WITH (UPDLOCK, ROWLOCK) ON [q].[id] = [j].[queue_id] WHERE [j].[status] = @p1 ORDER BY [j].[id]`
+	wantArgs := []any{sql.Named("p1", "ready")}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectRowLockingErrors(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	bar := sqlb.NewTable("bar", "b")
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLite{})
+	q := sqlb.NewSelectBuilder().Select(foo.Column("*")).From(foo).ForUpdate()
+	if _, _, err := q.Build(ctx); err == nil {
+		t.Error("SQLite: want error, got nil")
+	}
+	ctx = sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	q = sqlb.NewSelectBuilder().
+		Select(foo.Column("id")).From(foo).
+		Union(sqlb.NewSelectBuilder().Select(bar.Column("id")).From(bar)).
+		ForUpdate()
+	if _, _, err := q.Build(ctx); err == nil {
+		t.Error("UNION: want error, got nil")
+	}
+	q = sqlb.NewSelectBuilder().Select(foo.Column("*")).From(foo).SkipLocked()
+	if _, _, err := q.Build(ctx); err == nil {
+		t.Error("SKIP LOCKED without lock: want error, got nil")
+	}
+	ctx = sqlb.NewContext(context.Background(), dialect.Oracle{})
+	q = sqlb.NewSelectBuilder().Select(foo.Column("*")).From(foo).ForShare()
+	if _, _, err := q.Build(ctx); err == nil {
+		t.Error("Oracle FOR SHARE: want error, got nil")
+	}
+}
+
+func TestSelectRowLockingKeepsTable(t *testing.T) {
+	var (
+		jobs   = sqlb.NewTable("jobs", "j")
+		owners = sqlb.NewTable("owners", "o")
+	)
+	b := sqlb.NewSelectBuilder().
+		EnableElimination().
+		Select(jobs.Column("id")).
+		From(jobs).
+		LeftJoinOptional(owners, sqlf.F("? = ?", owners.Column("id"), jobs.Column("owner_id"))).
+		ForUpdate().
+		Of(jobs, owners)
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := b.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT "j"."id" FROM "jobs" AS "j" LEFT JOIN "owners" AS "o" ON "o"."id" = "j"."owner_id" FOR UPDATE OF "j", "o"`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
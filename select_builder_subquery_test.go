@@ -0,0 +1,387 @@
+package sqlb_test
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/qjebbs/go-sqlb"
+	"github.com/qjebbs/go-sqlb/dialect"
+	"github.com/qjebbs/go-sqlf/v4"
+)
+
+func TestSelectBuilderFromSubquery(t *testing.T) {
+	var (
+		orders = sqlb.NewTable("orders", "o")
+		s      = sqlb.NewTable("s")
+	)
+	sub := sqlb.NewSelectBuilder().
+		Select(orders.Column("user_id"), sqlf.F("COUNT(*) AS cnt")).
+		From(orders).
+		Where(sqlf.F("? = ?", orders.Column("status"), "paid")).
+		GroupBy(orders.Column("user_id"))
+	q := sqlb.NewSelectBuilder().
+		Select(s.Columns("user_id", "cnt")...).
+		FromSubquery(sub, s).
+		Where(sqlf.F("? > ?", s.Column("cnt"), 1))
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT "s"."user_id", "s"."cnt" FROM (SELECT "o"."user_id", COUNT(*) AS cnt FROM "orders" AS "o" WHERE "o"."status" = $1 GROUP BY "o"."user_id") AS "s" WHERE "s"."cnt" > $2`
+	wantArgs := []any{"paid", 1}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectBuilderJoinSubqueryElimination(t *testing.T) {
+	var (
+		users  = sqlb.NewTable("users", "u")
+		orders = sqlb.NewTable("orders", "o")
+		paid   = sqlb.NewTable("paid", "p")
+		total  = sqlb.NewTable("total")
+		unused = sqlb.NewTable("unused")
+	)
+	newSub := func() *sqlb.SelectBuilder {
+		return sqlb.NewSelectBuilder().
+			Select(orders.Column("user_id"), sqlf.F("SUM(?) AS amount", orders.Column("amount"))).
+			From(orders).
+			InnerJoin(paid, sqlf.F("? = ?", paid.Column("order_id"), orders.Column("id"))).
+			GroupBy(orders.Column("user_id"))
+	}
+	q := sqlb.NewSelectBuilder().
+		EnableElimination().
+		With(paid, sqlf.F("SELECT order_id FROM payments")).
+		Select(users.Column("id"), total.Column("amount")).
+		From(users).
+		JoinSubquery(sqlb.JoinLeftOptional, newSub(), total, sqlf.F(
+			"? = ?", total.Column("user_id"), users.Column("id"),
+		)).
+		JoinSubquery(sqlb.JoinLeftOptional, newSub(), unused, sqlf.F( // not referenced, should be ignored
+			"? = ?", unused.Column("user_id"), users.Column("id"),
+		))
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the CTE referenced only by the derived table is kept
+	wantQuery := `WITH "paid" AS (SELECT order_id FROM payments) SELECT "u"."id", "total"."amount" FROM "users" AS "u" LEFT JOIN (SELECT "o"."user_id", SUM("o"."amount") AS amount FROM "orders" AS "o" INNER JOIN "paid" AS "p" ON "p"."order_id" = "o"."id" GROUP BY "o"."user_id") AS "total" ON "total"."user_id" = "u"."id"`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectBuilderJoinSubqueryOuterReference(t *testing.T) {
+	var (
+		users  = sqlb.NewTable("users", "u")
+		foo    = sqlb.NewTable("foo", "f")
+		bar    = sqlb.NewTable("bar", "b")
+		orders = sqlb.NewTable("orders", "o")
+		last   = sqlb.NewTable("last")
+	)
+	// the derived table references "b" of the outermost query
+	sub := sqlb.NewSelectBuilder().
+		Select(orders.Column("user_id")).
+		From(orders).
+		Where(sqlf.F("? = ?", orders.Column("bar_id"), bar.Column("id")))
+	exists := sqlb.NewSelectBuilder().
+		Select(sqlf.F("1")).
+		From(users).
+		JoinSubquery(sqlb.JoinInner, sub, last, sqlf.F("? = ?", last.Column("user_id"), users.Column("id")))
+	q := sqlb.NewSelectBuilder().
+		EnableElimination().
+		Select(foo.Column("id")).
+		From(foo).
+		LeftJoinOptional(bar, sqlf.F("? = ?", bar.Column("foo_id"), foo.Column("id"))).
+		Where(sqlf.F("EXISTS (?)", exists))
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT "f"."id" FROM "foo" AS "f" LEFT JOIN "bar" AS "b" ON "b"."foo_id" = "f"."id" WHERE EXISTS (SELECT 1 FROM "users" AS "u" INNER JOIN (SELECT "o"."user_id" FROM "orders" AS "o" WHERE "o"."bar_id" = "b"."id") AS "last" ON "last"."user_id" = "u"."id")`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectBuilderSubqueryErrors(t *testing.T) {
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	foo := sqlb.NewTable("foo", "f")
+	q := sqlb.NewSelectBuilder().Select(sqlf.F("1")).FromSubquery(nil, sqlb.NewTable("s"))
+	if _, _, err := q.Build(ctx); err == nil {
+		t.Error("nil subquery: want error, got nil")
+	}
+	q = sqlb.NewSelectBuilder().Select(sqlf.F("1")).From(foo).JoinSubquery(sqlb.JoinInner, sqlf.F("SELECT 1"), sqlb.Table{}, nil)
+	if _, _, err := q.Build(ctx); err == nil {
+		t.Error("no alias: want error, got nil")
+	}
+	q = sqlb.NewSelectBuilder().Select(sqlf.F("1")).From(foo).JoinSubquery(sqlb.JoinKind(-1), sqlf.F("SELECT 1"), sqlb.NewTable("s"), nil)
+	if _, _, err := q.Build(ctx); err == nil {
+		t.Error("bad join kind: want error, got nil")
+	}
+}
+
+func TestSelectBuilderLateralJoin(t *testing.T) {
+	var (
+		users  = sqlb.NewTable("users", "u")
+		orders = sqlb.NewTable("orders", "o")
+		last   = sqlb.NewTable("last")
+	)
+	sub := sqlb.NewSelectBuilder().
+		Select(orders.Column("amount")).
+		From(orders).
+		Where(sqlf.F("? = ?", orders.Column("user_id"), users.Column("id"))).
+		WhereEquals(orders.Column("status"), "paid").
+		OrderBy(sqlf.F("? DESC", orders.Column("created_at"))).
+		Limit(1)
+	q := sqlb.NewSelectBuilder().
+		Select(users.Column("id"), last.Column("amount")).
+		From(users).
+		LateralJoin(sub, last)
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT "u"."id", "last"."amount" FROM "users" AS "u" CROSS JOIN LATERAL (SELECT "o"."amount" FROM "orders" AS "o" WHERE "o"."user_id" = "u"."id" AND "o"."status" = $1 ORDER BY "o"."created_at" DESC LIMIT 1) AS "last"`
+	wantArgs := []any{"paid"}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectBuilderLeftLateralJoin(t *testing.T) {
+	var (
+		users  = sqlb.NewTable("users", "u")
+		orders = sqlb.NewTable("orders", "o")
+		last   = sqlb.NewTable("last")
+	)
+	sub := sqlb.NewSelectBuilder().
+		Select(orders.Column("amount")).
+		From(orders).
+		Where(sqlf.F("? = ?", orders.Column("user_id"), users.Column("id"))).
+		WhereEquals(orders.Column("status"), "paid").
+		OrderBy(sqlf.F("? DESC", orders.Column("created_at"))).
+		Limit(1)
+	q := sqlb.NewSelectBuilder().
+		Select(users.Column("id"), last.Column("amount")).
+		From(users).
+		LeftLateralJoin(sub, last)
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT "u"."id", "last"."amount" FROM "users" AS "u" LEFT JOIN LATERAL (SELECT "o"."amount" FROM "orders" AS "o" WHERE "o"."user_id" = "u"."id" AND "o"."status" = $1 ORDER BY "o"."created_at" DESC LIMIT 1) AS "last" ON TRUE`
+	wantArgs := []any{"paid"}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectBuilderLeftLateralJoinMySQL(t *testing.T) {
+	var (
+		users  = sqlb.NewTable("users", "u")
+		orders = sqlb.NewTable("orders", "o")
+		last   = sqlb.NewTable("last")
+	)
+	sub := sqlb.NewSelectBuilder().
+		Select(orders.Column("amount")).
+		From(orders).
+		Where(sqlf.F("? = ?", orders.Column("user_id"), users.Column("id"))).
+		WhereEquals(orders.Column("status"), "paid").
+		OrderBy(sqlf.F("? DESC", orders.Column("created_at"))).
+		Limit(1)
+	q := sqlb.NewSelectBuilder().
+		Select(users.Column("id"), last.Column("amount")).
+		From(users).
+		LeftLateralJoin(sub, last)
+	ctx := sqlb.NewContext(context.Background(), dialect.MySQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := "SELECT `u`.`id`, `last`.`amount` FROM `users` AS `u` LEFT JOIN LATERAL (SELECT `o`.`amount` FROM `orders` AS `o` WHERE `o`.`user_id` = `u`.`id` AND `o`.`status` = ? ORDER BY `o`.`created_at` DESC LIMIT 1) AS `last` ON TRUE"
+	wantArgs := []any{"paid"}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectBuilderLateralJoinSQLServer(t *testing.T) {
+	var (
+		users  = sqlb.NewTable("users", "u")
+		orders = sqlb.NewTable("orders", "o")
+		last   = sqlb.NewTable("last")
+	)
+	sub := sqlb.NewSelectBuilder().
+		Select(orders.Column("amount")).
+		From(orders).
+		Where(sqlf.F("? = ?", orders.Column("user_id"), users.Column("id"))).
+		WhereEquals(orders.Column("status"), "paid").
+		OrderBy(sqlf.F("? DESC", orders.Column("created_at"))).
+		Limit(1)
+	q := sqlb.NewSelectBuilder().
+		Select(users.Column("id"), last.Column("amount")).
+		From(users).
+		LateralJoin(sub, last)
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLServer{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT [u].[id], [last].[amount] FROM [users] AS [u] CROSS APPLY (SELECT TOP 1 [o].[amount] FROM [orders] AS [o] WHERE [o].[user_id] = [u].[id] AND [o].[status] = @p1 ORDER BY [o].[created_at] DESC) AS [last]`
+	wantArgs := []any{sql.Named("p1", "paid")}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectBuilderLeftLateralJoinSQLServer(t *testing.T) {
+	var (
+		users  = sqlb.NewTable("users", "u")
+		orders = sqlb.NewTable("orders", "o")
+		last   = sqlb.NewTable("last")
+	)
+	sub := sqlb.NewSelectBuilder().
+		Select(orders.Column("amount")).
+		From(orders).
+		Where(sqlf.F("? = ?", orders.Column("user_id"), users.Column("id"))).
+		WhereEquals(orders.Column("status"), "paid").
+		OrderBy(sqlf.F("? DESC", orders.Column("created_at"))).
+		Limit(1)
+	q := sqlb.NewSelectBuilder().
+		Select(users.Column("id"), last.Column("amount")).
+		From(users).
+		LeftLateralJoin(sub, last)
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLServer{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT [u].[id], [last].[amount] FROM [users] AS [u] OUTER APPLY (SELECT TOP 1 [o].[amount] FROM [orders] AS [o] WHERE [o].[user_id] = [u].[id] AND [o].[status] = @p1 ORDER BY [o].[created_at] DESC) AS [last]`
+	wantArgs := []any{sql.Named("p1", "paid")}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectBuilderLateralJoinUnsupported(t *testing.T) {
+	var (
+		users  = sqlb.NewTable("users", "u")
+		orders = sqlb.NewTable("orders", "o")
+		last   = sqlb.NewTable("last")
+	)
+	sub := sqlb.NewSelectBuilder().
+		Select(orders.Column("amount")).
+		From(orders).
+		Where(sqlf.F("? = ?", orders.Column("user_id"), users.Column("id"))).
+		Limit(1)
+	q := sqlb.NewSelectBuilder().
+		Select(users.Column("id"), last.Column("amount")).
+		From(users).
+		LeftLateralJoin(sub, last)
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLite{})
+	if _, _, err := q.Build(ctx); err == nil {
+		t.Error("want error, got nil")
+	}
+}
+
+func TestSelectBuilderLateralJoinElimination(t *testing.T) {
+	var (
+		foo    = sqlb.NewTable("foo", "f")
+		users  = sqlb.NewTable("users", "u")
+		orders = sqlb.NewTable("orders", "o")
+		last   = sqlb.NewTable("last")
+	)
+	sub := sqlb.NewSelectBuilder().
+		Select(orders.Column("amount")).
+		From(orders).
+		Where(sqlf.F("? = ?", orders.Column("user_id"), users.Column("id"))).
+		WhereEquals(orders.Column("status"), "paid").
+		Limit(1)
+	q := sqlb.NewSelectBuilder().
+		EnableElimination().
+		Select(foo.Column("id")).
+		From(foo).
+		LeftJoinOptional(users, sqlf.F("? = ?", users.Column("id"), foo.Column("user_id"))).
+		LeftLateralJoinOptional(sub, last)
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT "f"."id" FROM "foo" AS "f"`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectBuilderLateralJoinKeepsCorrelated(t *testing.T) {
+	var (
+		foo    = sqlb.NewTable("foo", "f")
+		users  = sqlb.NewTable("users", "u")
+		orders = sqlb.NewTable("orders", "o")
+		last   = sqlb.NewTable("last")
+	)
+	sub := sqlb.NewSelectBuilder().
+		Select(orders.Column("amount")).
+		From(orders).
+		Where(sqlf.F("? = ?", orders.Column("user_id"), users.Column("id"))).
+		WhereEquals(orders.Column("status"), "paid").
+		Limit(1)
+	q := sqlb.NewSelectBuilder().
+		EnableElimination().
+		Select(foo.Column("id"), last.Column("amount")).
+		From(foo).
+		LeftJoinOptional(users, sqlf.F("? = ?", users.Column("id"), foo.Column("user_id"))).
+		LeftLateralJoinOptional(sub, last)
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT "f"."id", "last"."amount" FROM "foo" AS "f" LEFT JOIN "users" AS "u" ON "u"."id" = "f"."user_id" LEFT JOIN LATERAL (SELECT "o"."amount" FROM "orders" AS "o" WHERE "o"."user_id" = "u"."id" AND "o"."status" = $1 LIMIT 1) AS "last" ON TRUE`
+	wantArgs := []any{"paid"}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
@@ -166,3 +166,48 @@ func TestSelectBuilderComplexDeps(t *testing.T) {
 		t.Errorf("got:\n%s\nwant:\n%s", query, wantQuery)
 	}
 }
+
+func TestSelectBuilderPagination(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	testCases := []struct {
+		dialect dialect.Dialect
+		offset  int64
+		want    string
+	}{
+		{dialect.PostgreSQL{}, 20, `SELECT DISTINCT "f"."id" FROM "foo" AS "f" ORDER BY "f"."id" LIMIT 10 OFFSET 20`},
+		{dialect.SQLServer{}, 0, `SELECT DISTINCT TOP 10 [f].[id] FROM [foo] AS [f] ORDER BY [f].[id]`},
+		{dialect.SQLServer{}, 20, `SELECT DISTINCT [f].[id] FROM [foo] AS [f] ORDER BY [f].[id] OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY`},
+		{dialect.Oracle{}, 0, `SELECT DISTINCT "f"."id" FROM "foo" AS "f" ORDER BY "f"."id" FETCH NEXT 10 ROWS ONLY`},
+		{dialect.Oracle{}, 20, `SELECT DISTINCT "f"."id" FROM "foo" AS "f" ORDER BY "f"."id" OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY`},
+	}
+	for _, tc := range testCases {
+		q := sqlb.NewSelectBuilder().
+			Distinct().
+			Select(foo.Column("id")).
+			From(foo).
+			OrderBy(foo.Column("id")).
+			Limit(10).
+			Offset(tc.offset)
+		ctx := sqlb.NewContext(context.Background(), tc.dialect)
+		got, _, err := q.Build(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tc.want != got {
+			t.Errorf("got:\n%s\nwant:\n%s", got, tc.want)
+		}
+	}
+}
+
+func TestSelectBuilderOffsetUnorderedSQLServer(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	q := sqlb.NewSelectBuilder().
+		Select(foo.Column("id")).
+		From(foo).
+		Limit(10).
+		Offset(20)
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLServer{})
+	if _, _, err := q.Build(ctx); err == nil {
+		t.Error("want error, got nil")
+	}
+}
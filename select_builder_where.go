@@ -2,7 +2,6 @@ package sqlb
 
 import (
 	"github.com/qjebbs/go-sqlf/v4"
-	"github.com/qjebbs/go-sqlf/v4/util"
 )
 
 // Where add a condition.  e.g.:
@@ -88,23 +87,128 @@ func (b *SelectBuilder) WhereNotBetween(column sqlf.Builder, start, end any) *Se
 }
 
 // WhereIn adds a where IN condition like `t.id IN (1,2,3)`
+//
+// Lists longer than the threshold are rendered with the LargeInListStrategy
+// of the dialect, see ContextWithInListThreshold.
+// An empty list is built as an always-false predicate, see ContextWithStrictInList.
 func (b *SelectBuilder) WhereIn(column sqlf.Builder, list any) *SelectBuilder {
 	return b.Where(
-		sqlf.F(
-			"? IN (?)",
-			column,
-			sqlf.JoinArgs(util.FlattenArgs(list), ", "),
-		),
+		newInPredicate(column, list, false),
 	)
 }
 
 // WhereNotIn adds a where NOT IN condition like `t.id NOT IN (1,2,3)`
+//
+// Lists longer than the threshold are rendered with the LargeInListStrategy
+// of the dialect, see ContextWithInListThreshold.
+// An empty list is built as an always-true predicate, see ContextWithStrictInList.
 func (b *SelectBuilder) WhereNotIn(column sqlf.Builder, list any) *SelectBuilder {
 	return b.Where(
-		sqlf.F(
-			"? NOT IN (?)",
-			column,
-			sqlf.JoinArgs(util.FlattenArgs(list), ", "),
-		),
+		newInPredicate(column, list, true),
+	)
+}
+
+// WhereContains adds a LIKE condition like `t.name LIKE '%foo%' ESCAPE '!'`,
+// in which the wildcards in value are escaped to be matched literally.
+func (b *SelectBuilder) WhereContains(column sqlf.Builder, value string) *SelectBuilder {
+	return b.Where(
+		&likePredicate{column: column, value: value, prefix: true, suffix: true},
+	)
+}
+
+// WhereStartsWith adds a LIKE condition like `t.name LIKE 'foo%' ESCAPE '!'`,
+// in which the wildcards in value are escaped to be matched literally.
+func (b *SelectBuilder) WhereStartsWith(column sqlf.Builder, value string) *SelectBuilder {
+	return b.Where(
+		&likePredicate{column: column, value: value, suffix: true},
+	)
+}
+
+// WhereEndsWith adds a LIKE condition like `t.name LIKE '%foo' ESCAPE '!'`,
+// in which the wildcards in value are escaped to be matched literally.
+func (b *SelectBuilder) WhereEndsWith(column sqlf.Builder, value string) *SelectBuilder {
+	return b.Where(
+		&likePredicate{column: column, value: value, prefix: true},
+	)
+}
+
+// WhereContainsIgnoreCase is the case-insensitive version of WhereContains,
+// which is built as `t.name ILIKE ?` if supported by the dialect,
+// otherwise `LOWER(t.name) LIKE LOWER(?)`.
+func (b *SelectBuilder) WhereContainsIgnoreCase(column sqlf.Builder, value string) *SelectBuilder {
+	return b.Where(
+		&likePredicate{column: column, value: value, prefix: true, suffix: true, ignoreCase: true},
+	)
+}
+
+// WhereStartsWithIgnoreCase is the case-insensitive version of WhereStartsWith,
+// which is built as `t.name ILIKE ?` if supported by the dialect,
+// otherwise `LOWER(t.name) LIKE LOWER(?)`.
+func (b *SelectBuilder) WhereStartsWithIgnoreCase(column sqlf.Builder, value string) *SelectBuilder {
+	return b.Where(
+		&likePredicate{column: column, value: value, suffix: true, ignoreCase: true},
+	)
+}
+
+// WhereEndsWithIgnoreCase is the case-insensitive version of WhereEndsWith,
+// which is built as `t.name ILIKE ?` if supported by the dialect,
+// otherwise `LOWER(t.name) LIKE LOWER(?)`.
+func (b *SelectBuilder) WhereEndsWithIgnoreCase(column sqlf.Builder, value string) *SelectBuilder {
+	return b.Where(
+		&likePredicate{column: column, value: value, prefix: true, ignoreCase: true},
+	)
+}
+
+// WhereTupleIn adds a row value IN condition like `(t.a, t.b) IN ((1,2),(3,4))`,
+// which is emulated with OR-ed AND conditions on dialects without row values.
+//
+//	b.WhereTupleIn(t.Columns("a", "b"), [][]any{{1, 2}, {3, 4}})
+func (b *SelectBuilder) WhereTupleIn(columns []sqlf.Builder, rows [][]any) *SelectBuilder {
+	return b.Where(
+		&tupleInPredicate{columns: columns, rows: rows},
+	)
+}
+
+// WhereTupleCompare adds a row value comparison like `(t.a, t.b) > (1,2)`,
+// which is emulated with lexicographic OR-ed AND conditions on dialects without row values.
+// The op is one of "=", "<>", "<", "<=", ">", ">=".
+//
+//	b.WhereTupleCompare(t.Columns("created_at", "id"), ">", lastCreatedAt, lastID)
+func (b *SelectBuilder) WhereTupleCompare(columns []sqlf.Builder, op string, values ...any) *SelectBuilder {
+	return b.Where(
+		&tupleComparePredicate{columns: columns, op: op, values: values},
+	)
+}
+
+// WhereMatches adds a full-text search condition of the natural language query
+// against columns, which requires the full-text index of the dialect:
+//
+//	PostgreSQL: to_tsvector(t.title) @@ plainto_tsquery(?)
+//	MySQL:      MATCH (t.title, t.body) AGAINST (? IN NATURAL LANGUAGE MODE)
+//	SQLServer:  FREETEXT((t.title, t.body), ?)
+//	SQLite:     t.title MATCH ?
+//
+// On SQLite, the columns must be of one FTS5 virtual table, and multiple
+// columns are matched as the whole table, e.g. `"t"."docs" MATCH ?`.
+// An empty query matches nothing. See MatchScore for the relevance score.
+func (b *SelectBuilder) WhereMatches(columns []sqlf.Builder, query string) *SelectBuilder {
+	return b.Where(
+		&matchPredicate{columns: columns, query: query},
+	)
+}
+
+// WhereMatchesBoolean is like WhereMatches, but the query is written in the
+// boolean query syntax of the dialect, e.g. `foo & !bar` for PostgreSQL:
+//
+//	PostgreSQL: to_tsvector(t.title) @@ to_tsquery(?)
+//	MySQL:      MATCH (t.title, t.body) AGAINST (? IN BOOLEAN MODE)
+//	SQLServer:  CONTAINS((t.title, t.body), ?)
+//	SQLite:     t.title MATCH ?
+//
+// The query is passed to the database as is, which must be valid
+// for the dialect.
+func (b *SelectBuilder) WhereMatchesBoolean(columns []sqlf.Builder, query string) *SelectBuilder {
+	return b.Where(
+		&matchPredicate{columns: columns, query: query, boolean: true},
 	)
 }
@@ -0,0 +1,82 @@
+package sqlb
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/qjebbs/go-sqlb/dialect"
+	"github.com/qjebbs/go-sqlf/v4"
+)
+
+// NextVal returns the next value of the sequence, which can be used
+// in InsertBuilder.Values, e.g.:
+//
+//	b.InsertInto("orders").Columns("id", "name").Values(sqlb.NextVal("order_seq"), "foo")
+//	// PostgreSQL: nextval('"order_seq"')
+//	// SQLServer:  NEXT VALUE FOR [order_seq]
+//	// Oracle:     "order_seq".NEXTVAL
+//
+// The sequence can be qualified by the schema, e.g. "public.order_seq",
+// in which each part is quoted separately.
+//
+// The build fails on the dialects without sequences, e.g. MySQL and SQLite.
+func NextVal(sequence string) sqlf.Builder {
+	return sqlf.Func(func(ctx sqlf.Context) (string, error) {
+		uCtx, err := contextUpgrade(ctx)
+		if err != nil {
+			return "", err
+		}
+		name := quoteQualifiedName(uCtx, sequence)
+		switch uCtx.Dialect().Capabilities().Sequences {
+		case dialect.SequenceNextValFunction:
+			// nextval() accepts the sequence name as a string literal
+			return "nextval('" + strings.ReplaceAll(name, "'", "''") + "')", nil
+		case dialect.SequenceNextValueFor:
+			return "NEXT VALUE FOR " + name, nil
+		case dialect.SequencePseudoColumn:
+			return name + ".NEXTVAL", nil
+		default:
+			return "", fmt.Errorf("NextVal: sequences are not supported by %T", uCtx.BaseDialect())
+		}
+	})
+}
+
+// quoteQualifiedName quotes each part of the dot-separated name,
+// e.g. "public"."order_seq".
+func quoteQualifiedName(ctx Context, name string) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		parts[i] = ctx.BaseDialect().QuoteIdentifier(part)
+	}
+	return strings.Join(parts, ".")
+}
+
+// LastInsertID returns the last value generated by the identity / auto-increment
+// column in the current session, e.g.:
+//
+//	MySQL:      LAST_INSERT_ID()
+//	SQLServer:  SCOPE_IDENTITY()
+//	SQLite:     last_insert_rowid()
+//	PostgreSQL: lastval()
+//
+// It must be executed on the same connection as the INSERT statement.
+// See also InsertBuilder.BuildStatements for the dialects without RETURNING.
+//
+// It's not supported by Oracle, which has no such session function, and whose
+// identity columns use the sequences named by the system. Get the value with
+// NextVal of your own sequence before the insert instead, see
+// dialect.Capabilities.LastInsertIDExpression.
+func LastInsertID() sqlf.Builder {
+	return sqlf.Func(func(ctx sqlf.Context) (string, error) {
+		uCtx, err := contextUpgrade(ctx)
+		if err != nil {
+			return "", err
+		}
+		d := uCtx.Dialect()
+		expr := d.Capabilities().LastInsertIDExpression
+		if expr == "" {
+			return "", fmt.Errorf("LastInsertID: not supported by %T", d)
+		}
+		return expr, nil
+	})
+}
@@ -0,0 +1,173 @@
+package sqlb_test
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/qjebbs/go-sqlb"
+	"github.com/qjebbs/go-sqlb/dialect"
+	"github.com/qjebbs/go-sqlf/v4"
+)
+
+func TestInsertNextValPostgreSQL(t *testing.T) {
+	q := sqlb.NewInsertBuilder().
+		InsertInto("orders").
+		Columns("id", "name").
+		Values(sqlb.NextVal("order_seq"), "foo")
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `INSERT INTO "orders" ("id", "name") VALUES (nextval('"order_seq"'), $1)`
+	wantArgs := []any{"foo"}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestInsertNextValSchemaQualifiedPostgreSQL(t *testing.T) {
+	q := sqlb.NewInsertBuilder().
+		InsertInto("orders").
+		Columns("id", "name").
+		Values(sqlb.NextVal("public.order_seq"), "foo")
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `INSERT INTO "orders" ("id", "name") VALUES (nextval('"public"."order_seq"'), $1)`
+	wantArgs := []any{"foo"}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestInsertNextValSQLServer(t *testing.T) {
+	q := sqlb.NewInsertBuilder().
+		InsertInto("orders").
+		Columns("id", "name").
+		Values(sqlb.NextVal("dbo.order_seq"), "foo")
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLServer{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `INSERT INTO [orders] ([id], [name]) VALUES (NEXT VALUE FOR [dbo].[order_seq], @p1)`
+	wantArgs := []any{sql.Named("p1", "foo")}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestInsertNextValOracle(t *testing.T) {
+	q := sqlb.NewInsertBuilder().
+		InsertInto("orders").
+		Columns("id", "name").
+		Values(sqlb.NextVal("order_seq"), "foo")
+	ctx := sqlb.NewContext(context.Background(), dialect.Oracle{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `INSERT INTO "orders" ("id", "name") VALUES ("order_seq".NEXTVAL, :1)`
+	wantArgs := []any{"foo"}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestInsertNextValUnsupported(t *testing.T) {
+	ctx := sqlb.NewContext(context.Background(), dialect.MySQL{})
+	_, _, err := sqlb.NewInsertBuilder().
+		InsertInto("orders").
+		Values(sqlb.NextVal("order_seq")).
+		Build(ctx)
+	if err == nil {
+		t.Error("want error for sequences on MySQL, got nil")
+	}
+}
+
+func TestInsertBuildStatementsEmulated(t *testing.T) {
+	q := sqlb.NewInsertBuilder().
+		InsertInto("foo").
+		Columns("name").
+		Values("bar").
+		Returning("id")
+	ctx := sqlb.NewContext(context.Background(), dialect.MySQL{})
+	got, err := q.BuildStatements(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []sqlb.Statement{
+		{Query: "INSERT INTO `foo` (`name`) VALUES (?)", Args: []any{"bar"}},
+		{Query: "SELECT LAST_INSERT_ID() AS `id`"},
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want:\n%#v\ngot:\n%#v", want, got)
+	}
+	// the emulation is limited to single-row inserts
+	if _, err := q.Values("baz").BuildStatements(ctx); err == nil {
+		t.Error("want error for multi-row emulated returning, got nil")
+	}
+}
+
+func TestInsertBuildStatementsReturning(t *testing.T) {
+	q := sqlb.NewInsertBuilder().
+		InsertInto("foo").
+		Columns("name").
+		Values("bar").
+		Returning("id")
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	got, err := q.BuildStatements(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []sqlb.Statement{
+		{Query: `INSERT INTO "foo" ("name") VALUES ($1) RETURNING "id"`, Args: []any{"bar"}},
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want:\n%#v\ngot:\n%#v", want, got)
+	}
+}
+
+func TestInsertBuildStatementsEmulatedWrappedDialect(t *testing.T) {
+	q := sqlb.NewInsertBuilder().
+		InsertInto("foo").
+		Columns("name").
+		Values("bar").
+		Returning("id")
+	ctx := sqlb.NewContext(context.Background(), wrappedMySQL{})
+	got, err := q.BuildStatements(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []sqlb.Statement{
+		{Query: "INSERT INTO `foo` (`name`) VALUES (?)", Args: []any{"bar"}},
+		{Query: "SELECT LAST_INSERT_ID() AS `id`"},
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want:\n%#v\ngot:\n%#v", want, got)
+	}
+}
+
+func TestLastInsertIDUnsupported(t *testing.T) {
+	ctx := sqlb.NewContext(context.Background(), dialect.Oracle{})
+	if _, _, err := sqlf.Build(ctx, sqlb.LastInsertID()); err == nil {
+		t.Error("want error for LastInsertID on Oracle, got nil")
+	}
+}
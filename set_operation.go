@@ -0,0 +1,121 @@
+package sqlb
+
+import (
+	"fmt"
+
+	"github.com/qjebbs/go-sqlf/v4"
+)
+
+var _ sqlf.Builder = (*setOperation)(nil)
+
+// setOperation is an operand of UNION / INTERSECT / EXCEPT,
+// whose keywords are decided by the dialect at build time.
+type setOperation struct {
+	op      string // UNION, INTERSECT or EXCEPT
+	all     bool
+	builder sqlf.Builder
+}
+
+// BuildTo implements sqlf.Builder
+func (o *setOperation) BuildTo(ctx sqlf.Context) (string, error) {
+	uCtx, err := contextUpgrade(ctx)
+	if err != nil {
+		return "", err
+	}
+	caps := uCtx.Dialect().Capabilities()
+	op := o.op
+	if op == "EXCEPT" {
+		if caps.ExceptKeyword == "" {
+			return "", fmt.Errorf("EXCEPT is not supported by %T", uCtx.BaseDialect())
+		}
+		op = caps.ExceptKeyword
+	}
+	if o.all {
+		if op != "UNION" && !caps.SupportsSetOperationAll {
+			return "", fmt.Errorf("%s ALL is not supported by %T", op, uCtx.BaseDialect())
+		}
+		op += " ALL"
+	}
+	operand, err := buildSetOperand(uCtx, o.builder)
+	if err != nil {
+		return "", err
+	}
+	if operand == "" {
+		return "", nil
+	}
+	return op + " " + operand, nil
+}
+
+// buildSetOperand builds the operand of set operations, which is
+// parenthesized if it has its own ORDER BY / LIMIT / OFFSET, e.g.:
+//
+//	(SELECT ... ORDER BY x LIMIT 1)
+//	SELECT * FROM (SELECT ... ORDER BY x LIMIT 1)
+//
+// The operands must not lock rows, which is rejected by the databases.
+func buildSetOperand(ctx Context, b sqlf.Builder) (string, error) {
+	if sb, ok := b.(*SelectBuilder); ok && sb.lock.mode != lockNone {
+		return "", fmt.Errorf("row locking: not allowed with set operations")
+	}
+	query, err := b.BuildTo(ctx)
+	if err != nil || query == "" {
+		return query, err
+	}
+	if !isScopedSetOperand(b) {
+		return query, nil
+	}
+	return parenthesizeSetOperand(ctx, query), nil
+}
+
+// buildSetOperations appends the set operations to the built left query.
+//
+// INTERSECT binds tighter than UNION / EXCEPT in PostgreSQL, SQLServer and
+// MySQL, while SQLite and Oracle evaluate them from left to right. To have
+// the same result in all dialects, the accumulated left side is parenthesized
+// before an INTERSECT following UNION / EXCEPT, e.g.:
+//
+//	(a UNION b) INTERSECT c
+//	SELECT * FROM (a UNION b) INTERSECT c
+func buildSetOperations(ctx Context, left string, operations []sqlf.Builder) (string, error) {
+	mixed := false // left has UNION / EXCEPT at the top level
+	for _, o := range operations {
+		built, err := o.BuildTo(ctx)
+		if err != nil {
+			return "", err
+		}
+		if built == "" {
+			continue
+		}
+		if op, ok := o.(*setOperation); ok {
+			switch {
+			case op.op != "INTERSECT":
+				mixed = true
+			case mixed:
+				left = parenthesizeSetOperand(ctx, left)
+				mixed = false
+			}
+		}
+		left += " " + built
+	}
+	return left, nil
+}
+
+// parenthesizeSetOperand parenthesizes the built operand in the way of the dialect.
+func parenthesizeSetOperand(ctx Context, query string) string {
+	if ctx.Dialect().Capabilities().SupportsParenthesizedSetOperand {
+		return "(" + query + ")"
+	}
+	return "SELECT * FROM (" + query + ")"
+}
+
+// isScopedSetOperand reports whether the operand has clauses scoped to itself,
+// which must be parenthesized to not apply to the whole compound query.
+func isScopedSetOperand(b sqlf.Builder) bool {
+	switch b := b.(type) {
+	case *SelectBuilder:
+		return b.hasScopedClauses() || !b.unions.Empty()
+	case *CompoundBuilder:
+		return true
+	}
+	return false
+}
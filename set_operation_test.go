@@ -0,0 +1,345 @@
+package sqlb_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/qjebbs/go-sqlb"
+	"github.com/qjebbs/go-sqlb/dialect"
+	"github.com/qjebbs/go-sqlf/v4"
+)
+
+func TestSelectBuilderIntersect(t *testing.T) {
+	var (
+		foo = sqlb.NewTable("foo", "f")
+		bar = sqlb.NewTable("bar", "b")
+	)
+	q := sqlb.NewSelectBuilder().
+		Select(foo.Column("id")).
+		From(foo).
+		Where(sqlf.F("? = ?", foo.Column("type"), 1)).
+		Intersect(sqlb.NewSelectBuilder().
+			Select(bar.Column("id")).
+			From(bar).
+			Where(sqlf.F("? = ?", bar.Column("type"), 2)),
+		)
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT "f"."id" FROM "foo" AS "f" WHERE "f"."type" = $1 INTERSECT SELECT "b"."id" FROM "bar" AS "b" WHERE "b"."type" = $2`
+	wantArgs := []any{1, 2}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectBuilderIntersectAll(t *testing.T) {
+	var (
+		foo = sqlb.NewTable("foo", "f")
+		bar = sqlb.NewTable("bar", "b")
+	)
+	q := sqlb.NewSelectBuilder().
+		Select(foo.Column("id")).
+		From(foo).
+		Where(sqlf.F("? = ?", foo.Column("type"), 1)).
+		IntersectAll(sqlb.NewSelectBuilder().
+			Select(bar.Column("id")).
+			From(bar).
+			Where(sqlf.F("? = ?", bar.Column("type"), 2)),
+		)
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT "f"."id" FROM "foo" AS "f" WHERE "f"."type" = $1 INTERSECT ALL SELECT "b"."id" FROM "bar" AS "b" WHERE "b"."type" = $2`
+	wantArgs := []any{1, 2}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectBuilderExcept(t *testing.T) {
+	var (
+		foo = sqlb.NewTable("foo", "f")
+		bar = sqlb.NewTable("bar", "b")
+	)
+	q := sqlb.NewSelectBuilder().
+		Select(foo.Column("id")).
+		From(foo).
+		Where(sqlf.F("? = ?", foo.Column("type"), 1)).
+		Except(sqlb.NewSelectBuilder().
+			Select(bar.Column("id")).
+			From(bar).
+			Where(sqlf.F("? = ?", bar.Column("type"), 2)),
+		)
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLite{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT "f"."id" FROM "foo" AS "f" WHERE "f"."type" = ? EXCEPT SELECT "b"."id" FROM "bar" AS "b" WHERE "b"."type" = ?`
+	wantArgs := []any{1, 2}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectBuilderExceptAll(t *testing.T) {
+	var (
+		foo = sqlb.NewTable("foo", "f")
+		bar = sqlb.NewTable("bar", "b")
+	)
+	q := sqlb.NewSelectBuilder().
+		Select(foo.Column("id")).
+		From(foo).
+		Where(sqlf.F("? = ?", foo.Column("type"), 1)).
+		ExceptAll(sqlb.NewSelectBuilder().
+			Select(bar.Column("id")).
+			From(bar).
+			Where(sqlf.F("? = ?", bar.Column("type"), 2)),
+		)
+	ctx := sqlb.NewContext(context.Background(), dialect.MySQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := "SELECT `f`.`id` FROM `foo` AS `f` WHERE `f`.`type` = ? EXCEPT ALL SELECT `b`.`id` FROM `bar` AS `b` WHERE `b`.`type` = ?"
+	wantArgs := []any{1, 2}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectBuilderExceptOracle(t *testing.T) {
+	var (
+		foo = sqlb.NewTable("foo", "f")
+		bar = sqlb.NewTable("bar", "b")
+	)
+	q := sqlb.NewSelectBuilder().
+		Select(foo.Column("id")).
+		From(foo).
+		Where(sqlf.F("? = ?", foo.Column("type"), 1)).
+		Except(sqlb.NewSelectBuilder().
+			Select(bar.Column("id")).
+			From(bar).
+			Where(sqlf.F("? = ?", bar.Column("type"), 2)),
+		)
+	ctx := sqlb.NewContext(context.Background(), dialect.Oracle{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT "f"."id" FROM "foo" AS "f" WHERE "f"."type" = :1 MINUS SELECT "b"."id" FROM "bar" AS "b" WHERE "b"."type" = :2`
+	wantArgs := []any{1, 2}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectBuilderUnionExcept(t *testing.T) {
+	var (
+		foo = sqlb.NewTable("foo", "f")
+		bar = sqlb.NewTable("bar", "b")
+	)
+	q := sqlb.NewSelectBuilder().
+		Select(foo.Column("id")).
+		From(foo).
+		Union(sqlb.NewSelectBuilder().Select(bar.Column("id")).From(bar)).
+		Except(sqlb.NewSelectBuilder().Select(bar.Column("id")).From(bar))
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// UNION and EXCEPT have the same precedence
+	wantQuery := `SELECT "f"."id" FROM "foo" AS "f" UNION SELECT "b"."id" FROM "bar" AS "b" EXCEPT SELECT "b"."id" FROM "bar" AS "b"`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectBuilderSetOperationAllUnsupported(t *testing.T) {
+	var (
+		foo = sqlb.NewTable("foo", "f")
+		bar = sqlb.NewTable("bar", "b")
+	)
+	operand := sqlb.NewSelectBuilder().Select(bar.Column("id")).From(bar)
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLServer{})
+	q := sqlb.NewSelectBuilder().Select(foo.Column("id")).From(foo).ExceptAll(operand)
+	if _, _, err := q.Build(ctx); err == nil {
+		t.Error("SQLServer EXCEPT ALL: want error, got nil")
+	}
+	ctx = sqlb.NewContext(context.Background(), dialect.SQLite{})
+	q = sqlb.NewSelectBuilder().Select(foo.Column("id")).From(foo).IntersectAll(operand)
+	if _, _, err := q.Build(ctx); err == nil {
+		t.Error("SQLite INTERSECT ALL: want error, got nil")
+	}
+}
+
+func TestSelectBuilderSetOperationElimination(t *testing.T) {
+	var (
+		foo     = sqlb.NewTable("foo", "f")
+		bar     = sqlb.NewTable("bar", "b")
+		blocked = sqlb.NewTable("blocked")
+		unused  = sqlb.NewTable("unused")
+	)
+	q := sqlb.NewSelectBuilder().
+		EnableElimination().
+		With(blocked, sqlf.F("SELECT user_id FROM blocks")).
+		With(unused, sqlf.F("SELECT 1")).
+		Select(foo.Column("user_id")).
+		From(foo).
+		LeftJoinOptional(bar, sqlf.F("? = ?", bar.Column("id"), foo.Column("bar_id"))).
+		Except(
+			sqlb.NewSelectBuilder().
+				Select(blocked.Column("user_id")).
+				From(blocked),
+		)
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `WITH "blocked" AS (SELECT user_id FROM blocks) SELECT "f"."user_id" FROM "foo" AS "f" EXCEPT SELECT "blocked"."user_id" FROM "blocked"`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectBuilderMixedSetOperationsPostgreSQL(t *testing.T) {
+	var (
+		foo = sqlb.NewTable("foo", "f")
+		bar = sqlb.NewTable("bar", "b")
+		baz = sqlb.NewTable("baz", "z")
+	)
+	q := sqlb.NewSelectBuilder().
+		Select(foo.Column("id")).
+		From(foo).
+		Where(sqlf.F("? = ?", foo.Column("type"), 1)).
+		Union(sqlb.NewSelectBuilder().Select(bar.Column("id")).From(bar)).
+		Intersect(sqlb.NewSelectBuilder().
+			Select(baz.Column("id")).
+			From(baz).
+			Where(sqlf.F("? = ?", baz.Column("type"), 2)),
+		)
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `(SELECT "f"."id" FROM "foo" AS "f" WHERE "f"."type" = $1 UNION SELECT "b"."id" FROM "bar" AS "b") INTERSECT SELECT "z"."id" FROM "baz" AS "z" WHERE "z"."type" = $2`
+	wantArgs := []any{1, 2}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestCompoundBuilderMixedSetOperationsSQLite(t *testing.T) {
+	var (
+		foo = sqlb.NewTable("foo", "f")
+		bar = sqlb.NewTable("bar", "b")
+		baz = sqlb.NewTable("baz", "z")
+	)
+	q := sqlb.NewCompoundBuilder(
+		sqlb.NewSelectBuilder().
+			Select(foo.Column("id")).
+			From(foo).
+			Where(sqlf.F("? = ?", foo.Column("type"), 1)),
+	).
+		Except(sqlb.NewSelectBuilder().Select(bar.Column("id")).From(bar)).
+		Intersect(sqlb.NewSelectBuilder().
+			Select(baz.Column("id")).
+			From(baz).
+			Where(sqlf.F("? = ?", baz.Column("type"), 2)),
+		).
+		Union(sqlb.NewSelectBuilder().Select(bar.Column("id")).From(bar))
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLite{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT * FROM (SELECT "f"."id" FROM "foo" AS "f" WHERE "f"."type" = ? EXCEPT SELECT "b"."id" FROM "bar" AS "b") INTERSECT SELECT "z"."id" FROM "baz" AS "z" WHERE "z"."type" = ? UNION SELECT "b"."id" FROM "bar" AS "b"`
+	wantArgs := []any{1, 2}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectBuilderUnionLockedOperand(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	bar := sqlb.NewTable("bar", "b")
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	q := sqlb.NewSelectBuilder().
+		Select(foo.Column("id")).From(foo).
+		Union(sqlb.NewSelectBuilder().Select(bar.Column("id")).From(bar).ForUpdate())
+	if _, _, err := q.Build(ctx); err == nil {
+		t.Error("want error, got nil")
+	}
+}
+
+func TestCompoundBuilderMixedSetOperationsOracle(t *testing.T) {
+	var (
+		foo = sqlb.NewTable("foo", "f")
+		bar = sqlb.NewTable("bar", "b")
+		baz = sqlb.NewTable("baz", "z")
+	)
+	q := sqlb.NewCompoundBuilder(
+		sqlb.NewSelectBuilder().
+			Select(foo.Column("id")).
+			From(foo).
+			Where(sqlf.F("? = ?", foo.Column("type"), 1)),
+	).
+		Except(sqlb.NewSelectBuilder().Select(bar.Column("id")).From(bar)).
+		Intersect(sqlb.NewSelectBuilder().
+			Select(baz.Column("id")).
+			From(baz).
+			OrderBy(baz.Column("score")).
+			Limit(3),
+		)
+	ctx := sqlb.NewContext(context.Background(), dialect.Oracle{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `(SELECT "f"."id" FROM "foo" AS "f" WHERE "f"."type" = :1 MINUS SELECT "b"."id" FROM "bar" AS "b") INTERSECT (SELECT "z"."id" FROM "baz" AS "z" ORDER BY "z"."score" FETCH NEXT 3 ROWS ONLY)`
+	wantArgs := []any{1}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
@@ -9,6 +9,16 @@ import (
 	"github.com/qjebbs/go-sqlf/v4"
 )
 
+// wrappedPostgreSQL is a custom dialect embedding a built-in one.
+type wrappedPostgreSQL struct {
+	dialect.PostgreSQL
+}
+
+// wrappedMySQL is a custom dialect embedding a built-in one.
+type wrappedMySQL struct {
+	dialect.MySQL
+}
+
 func TestBuildToContext(t *testing.T) {
 	b := sqlf.F(
 		"WHERE IN (?)",
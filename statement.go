@@ -0,0 +1,32 @@
+package sqlb
+
+// Statement is a built SQL statement with its args.
+type Statement struct {
+	Query string
+	Args  []any
+}
+
+// StatementsBuilder is the interface for sql builders which may build
+// multiple statements, e.g. a setup statement before the query.
+// The statements should be executed in order within the same transaction.
+type StatementsBuilder interface {
+	// BuildStatements builds and returns the statements with the given context.
+	BuildStatements(ctx Context) ([]Statement, error)
+}
+
+type nestedStatementKey struct{}
+
+// contextWithNestedStatement returns a new context for building the statements
+// nested in the current one, e.g. subqueries, CTEs and set operation operands.
+func contextWithNestedStatement(ctx Context) Context {
+	if isNestedStatement(ctx) {
+		return ctx
+	}
+	return ContextWithValue(ctx, nestedStatementKey{}, struct{}{})
+}
+
+// isNestedStatement reports whether the statement is built inside another one,
+// which cannot have the statement level options, e.g. optimizer hints.
+func isNestedStatement(ctx Context) bool {
+	return ctx.Value(nestedStatementKey{}) != nil
+}
@@ -0,0 +1,78 @@
+package sqlb
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/qjebbs/go-sqlb/dialect"
+	"github.com/qjebbs/go-sqlf/v4"
+)
+
+// TableFunc is a table-valued function as the query source, e.g.
+// generate_series, unnest, json_each, JSON_TABLE and OPENJSON.
+//
+// The embedded Table holds the function name and the alias,
+// which is used to reference the columns, e.g.:
+//
+//	g := sqlb.NewTableFunc("generate_series", "g", 1, 10).WithColumns("n")
+//	b.Select(g.Column("n")).FromFunc(g)
+//	// SELECT "g"."n" FROM generate_series($1, $2) AS "g" (n)
+type TableFunc struct {
+	Table
+
+	// Args are the arguments of the function call, which are either
+	// sqlf.Builder, e.g. column references, or values bound as args.
+	Args []any
+	// Columns are the column definitions, e.g. "n" or "id INT PATH '$.id'",
+	// placed as the dialect requires, see dialect.TableFuncColumnStyle.
+	Columns []string
+}
+
+// NewTableFunc returns a new TableFunc.
+func NewTableFunc(name, alias string, args ...any) TableFunc {
+	return TableFunc{
+		Table: NewTable(name, alias),
+		Args:  args,
+	}
+}
+
+// WithColumns returns a new TableFunc with updated column definitions.
+func (f TableFunc) WithColumns(columns ...string) TableFunc {
+	f.Columns = columns
+	return f
+}
+
+// source returns the builder of the aliased function call, e.g.
+// `generate_series(1, 10) AS g (n)`.
+func (f TableFunc) source(alias Table) sqlf.Builder {
+	return sqlf.Func(func(ctx sqlf.Context) (query string, err error) {
+		uCtx, err := contextUpgrade(ctx)
+		if err != nil {
+			return "", err
+		}
+		// names and definitions are concatenated rather than templated,
+		// since definitions like `PATH '$.id'` may contain placeholder chars.
+		args, err := sqlf.JoinMixed(f.Args, ", ").BuildTo(ctx)
+		if err != nil {
+			return "", fmt.Errorf("build table function %s: %w", f.Name, err)
+		}
+		as, err := alias.BuildTo(ctx)
+		if err != nil {
+			return "", err
+		}
+		if len(f.Columns) == 0 {
+			return f.Name + "(" + args + ") AS " + as, nil
+		}
+		columns := strings.Join(f.Columns, ", ")
+		switch uCtx.Dialect().Capabilities().TableFuncColumns {
+		case dialect.TableFuncColumnsAlias:
+			return f.Name + "(" + args + ") AS " + as + " (" + columns + ")", nil
+		case dialect.TableFuncColumnsClause:
+			return f.Name + "(" + args + " COLUMNS (" + columns + ")) AS " + as, nil
+		case dialect.TableFuncColumnsWith:
+			return f.Name + "(" + args + ") WITH (" + columns + ") AS " + as, nil
+		default:
+			return "", fmt.Errorf("table function %s: column definitions are not supported by %T", f.Name, uCtx.BaseDialect())
+		}
+	})
+}
@@ -0,0 +1,205 @@
+package sqlb_test
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/qjebbs/go-sqlb"
+	"github.com/qjebbs/go-sqlb/dialect"
+	"github.com/qjebbs/go-sqlf/v4"
+)
+
+func TestSelectBuilderFromFuncGenerateSeries(t *testing.T) {
+	fn := sqlb.NewTableFunc("generate_series", "g", 1, 10).WithColumns("n")
+	q := sqlb.NewSelectBuilder().
+		Select(fn.Column("n")).
+		FromFunc(fn)
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT "g"."n" FROM generate_series($1, $2) AS "g" (n)`
+	wantArgs := []any{1, 10}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectBuilderFromFuncUnnest(t *testing.T) {
+	fn := sqlb.NewTableFunc("unnest", "u", sqlf.F("?::int[]", "{1,2}")).WithColumns("id")
+	q := sqlb.NewSelectBuilder().
+		Select(fn.Column("id")).
+		FromFunc(fn)
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT "u"."id" FROM unnest($1::int[]) AS "u" (id)`
+	wantArgs := []any{"{1,2}"}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectBuilderFromFuncJSONEach(t *testing.T) {
+	fn := sqlb.NewTableFunc("json_each", "j", `[1,2]`)
+	q := sqlb.NewSelectBuilder().
+		Select(fn.Column("value")).
+		FromFunc(fn)
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLite{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT "j"."value" FROM json_each(?) AS "j"`
+	wantArgs := []any{`[1,2]`}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectBuilderFromFuncJSONTable(t *testing.T) {
+	fn := sqlb.NewTableFunc("JSON_TABLE", "j", `[{"id":1}]`, sqlf.F("'$[*]'")).
+		WithColumns("id INT PATH '$.id'")
+	q := sqlb.NewSelectBuilder().
+		Select(fn.Column("id")).
+		FromFunc(fn)
+	ctx := sqlb.NewContext(context.Background(), dialect.MySQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := "SELECT `j`.`id` FROM JSON_TABLE(?, '$[*]' COLUMNS (id INT PATH '$.id')) AS `j`"
+	wantArgs := []any{`[{"id":1}]`}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectBuilderFromFuncOpenJSON(t *testing.T) {
+	fn := sqlb.NewTableFunc("OPENJSON", "j", `[{"id":1}]`).WithColumns("id INT '$.id'")
+	q := sqlb.NewSelectBuilder().
+		Select(fn.Column("id")).
+		FromFunc(fn)
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLServer{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT [j].[id] FROM OPENJSON(@p1) WITH (id INT '$.id') AS [j]`
+	wantArgs := []any{sql.Named("p1", `[{"id":1}]`)}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectBuilderJoinFunc(t *testing.T) {
+	var (
+		foo  = sqlb.NewTable("foo", "f")
+		tags = sqlb.NewTableFunc("OPENJSON", "t", foo.Column("tags")).WithColumns("tag NVARCHAR(50) '$'")
+	)
+	q := sqlb.NewSelectBuilder().
+		Select(foo.Column("id"), tags.Column("tag")).
+		From(foo).
+		Where(sqlf.F("? = ?", foo.Column("type"), "a")).
+		JoinFunc(sqlb.JoinCross, tags, nil)
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLServer{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT [f].[id], [t].[tag] FROM [foo] AS [f] CROSS APPLY OPENJSON([f].[tags]) WITH (tag NVARCHAR(50) '$') AS [t] WHERE [f].[type] = @p1`
+	wantArgs := []any{sql.Named("p1", "a")}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectBuilderJoinFuncElimination(t *testing.T) {
+	var (
+		foo  = sqlb.NewTable("foo", "f")
+		each = sqlb.NewTableFunc("json_each", "e", foo.Column("tags"))
+	)
+	q := sqlb.NewSelectBuilder().
+		EnableElimination().
+		Distinct().
+		Select(foo.Column("id")).
+		From(foo).
+		Where(sqlf.F("? = ?", foo.Column("type"), "a")).
+		JoinFunc(sqlb.JoinLeft, each, sqlf.F("TRUE"))
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLite{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT DISTINCT "f"."id" FROM "foo" AS "f" WHERE "f"."type" = ?`
+	wantArgs := []any{"a"}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectBuilderJoinFuncReferenced(t *testing.T) {
+	var (
+		foo  = sqlb.NewTable("foo", "f")
+		each = sqlb.NewTableFunc("json_each", "e", foo.Column("tags"))
+	)
+	q := sqlb.NewSelectBuilder().
+		EnableElimination().
+		Distinct().
+		Select(foo.Column("id"), each.Column("value")).
+		From(foo).
+		Where(sqlf.F("? = ?", foo.Column("type"), "a")).
+		JoinFunc(sqlb.JoinLeft, each, sqlf.F("TRUE"))
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLite{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT DISTINCT "f"."id", "e"."value" FROM "foo" AS "f" LEFT JOIN json_each("f"."tags") AS "e" ON TRUE WHERE "f"."type" = ?`
+	wantArgs := []any{"a"}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectBuilderFuncColumnsUnsupported(t *testing.T) {
+	fn := sqlb.NewTableFunc("json_each", "j", `[1]`).WithColumns("v")
+	b := sqlb.NewSelectBuilder().Select(fn.Column("v")).FromFunc(fn)
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLite{})
+	if _, _, err := b.Build(ctx); err == nil {
+		t.Error("want error for column definitions on SQLite, got nil")
+	}
+	b = sqlb.NewSelectBuilder().Select(sqlf.F("1")).FromFunc(sqlb.NewTableFunc("json_each", "", `[1]`))
+	if _, _, err := b.Build(ctx); err == nil {
+		t.Error("want error for function without alias, got nil")
+	}
+}
@@ -0,0 +1,143 @@
+package sqlb
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/qjebbs/go-sqlb/dialect"
+)
+
+var _ StatementsBuilder = (*SelectBuilder)(nil)
+var _ StatementsBuilder = (*UpdateBuilder)(nil)
+var _ StatementsBuilder = (*DeleteBuilder)(nil)
+
+type timeoutStatementKey struct{}
+
+// Timeout limits the execution time of the query at the SQL level,
+// in addition to the deadline of context.Context, e.g.:
+//
+//	MySQL:      SELECT /*+ MAX_EXECUTION_TIME(5000) */ ...
+//	PostgreSQL: SET LOCAL statement_timeout = 5000; SELECT ...
+//
+// On PostgreSQL, the query must be built with BuildStatements, and the
+// statements must be executed in order within the same transaction.
+//
+// The build fails on the dialects which cannot limit the execution time
+// of a query at the SQL level, see dialect.StatementTimeoutUnsupported:
+//
+//   - SQLServer has no query option for it. OPTION (...) hints cannot bound
+//     the execution time, and SET LOCK_TIMEOUT bounds the lock waits only,
+//     which also outlives the statement on the pooled connection.
+//   - Oracle has no such hint, and the limits of the Resource Manager are
+//     set for consumer groups by the DBA, rather than per statement.
+//   - SQLite has no SQL statement or hint for it.
+//
+// Use the deadline of context.Context for them instead, with which the
+// drivers cancel the running statement.
+//
+// The timeout applies to the top-level statement only, and is ignored when
+// the builder is nested in another one. A non-positive d removes the limit.
+func (b *SelectBuilder) Timeout(d time.Duration) *SelectBuilder {
+	b.timeout = max(d, 0)
+	return b
+}
+
+// Timeout limits the execution time of the statement at the SQL level,
+// which is supported by PostgreSQL only, see SelectBuilder.Timeout.
+func (b *InsertBuilder) Timeout(d time.Duration) *InsertBuilder {
+	b.timeout = max(d, 0)
+	return b
+}
+
+// Timeout limits the execution time of the statement at the SQL level,
+// which is supported by PostgreSQL only, see SelectBuilder.Timeout.
+func (b *UpdateBuilder) Timeout(d time.Duration) *UpdateBuilder {
+	b.timeout = max(d, 0)
+	return b
+}
+
+// Timeout limits the execution time of the statement at the SQL level,
+// which is supported by PostgreSQL only, see SelectBuilder.Timeout.
+func (b *DeleteBuilder) Timeout(d time.Duration) *DeleteBuilder {
+	b.timeout = max(d, 0)
+	return b
+}
+
+// BuildStatements builds the query, with the preceding setup statements
+// required by the dialect, e.g. `SET LOCAL statement_timeout` for Timeout().
+func (b *SelectBuilder) BuildStatements(ctx Context) ([]Statement, error) {
+	return buildWithTimeout(ctx, b, b.timeout)
+}
+
+// BuildStatements builds the statement, with the preceding setup statements
+// required by the dialect, e.g. `SET LOCAL statement_timeout` for Timeout().
+func (b *UpdateBuilder) BuildStatements(ctx Context) ([]Statement, error) {
+	return buildWithTimeout(ctx, b, b.timeout)
+}
+
+// BuildStatements builds the statement, with the preceding setup statements
+// required by the dialect, e.g. `SET LOCAL statement_timeout` for Timeout().
+func (b *DeleteBuilder) BuildStatements(ctx Context) ([]Statement, error) {
+	return buildWithTimeout(ctx, b, b.timeout)
+}
+
+// buildWithTimeout builds the statement with the setup statements of the timeout.
+func buildWithTimeout(ctx Context, b Builder, timeout time.Duration) ([]Statement, error) {
+	stmts, ctx := timeoutSetup(ctx, timeout)
+	query, args, err := b.Build(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return append(stmts, Statement{Query: query, Args: args}), nil
+}
+
+// timeoutSetup returns the setup statements limiting the execution time,
+// and the context which tells the statement that they are built.
+func timeoutSetup(ctx Context, timeout time.Duration) ([]Statement, Context) {
+	if timeout <= 0 || ctx.Dialect().Capabilities().StatementTimeout != dialect.StatementTimeoutSetLocal {
+		return nil, ctx
+	}
+	stmts := []Statement{{
+		// SET does not accept bind parameters
+		Query: fmt.Sprintf("SET LOCAL statement_timeout = %d", timeoutMilliseconds(timeout)),
+	}}
+	return stmts, ContextWithValue(ctx, timeoutStatementKey{}, struct{}{})
+}
+
+// timeoutHint returns the optimizer hint for the timeout of the top-level query,
+// or an error if the dialect cannot apply the timeout in the query.
+func timeoutHint(ctx Context, timeout time.Duration) (string, error) {
+	if timeout <= 0 {
+		return "", nil
+	}
+	if ctx.Dialect().Capabilities().StatementTimeout == dialect.StatementTimeoutHint {
+		return fmt.Sprintf("MAX_EXECUTION_TIME(%d)", timeoutMilliseconds(timeout)), nil
+	}
+	return "", checkTimeout(ctx, timeout)
+}
+
+// checkTimeout checks whether the timeout of the top-level data-modifying
+// statement can be applied by the setup statements.
+func checkTimeout(ctx Context, timeout time.Duration) error {
+	if timeout <= 0 {
+		return nil
+	}
+	d := ctx.Dialect()
+	switch d.Capabilities().StatementTimeout {
+	case dialect.StatementTimeoutSetLocal:
+		if ctx.Value(timeoutStatementKey{}) == nil {
+			return fmt.Errorf("timeout: %T requires building with BuildStatements", d)
+		}
+		return nil
+	case dialect.StatementTimeoutHint:
+		// e.g. MAX_EXECUTION_TIME of MySQL applies to SELECT only
+		return fmt.Errorf("timeout: not supported by %T for data-modifying statements", d)
+	default:
+		return fmt.Errorf("timeout: %T has no statement level timeout, use the deadline of context.Context instead", d)
+	}
+}
+
+// timeoutMilliseconds returns the timeout in milliseconds, at least 1.
+func timeoutMilliseconds(d time.Duration) int64 {
+	return max(d.Milliseconds(), 1)
+}
@@ -0,0 +1,179 @@
+package sqlb_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/qjebbs/go-sqlb"
+	"github.com/qjebbs/go-sqlb/dialect"
+	"github.com/qjebbs/go-sqlf/v4"
+)
+
+func TestSelectTimeoutMySQL(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	q := sqlb.NewSelectBuilder().
+		Select(foo.Column("id")).
+		From(foo).
+		WhereEquals(foo.Column("type"), 1).
+		Timeout(5 * time.Second)
+	ctx := sqlb.NewContext(context.Background(), dialect.MySQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := "SELECT /*+ MAX_EXECUTION_TIME(5000) */ `f`.`id` FROM `foo` AS `f` WHERE `f`.`type` = ?"
+	wantArgs := []any{1}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectTimeoutPostgreSQL(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	q := sqlb.NewSelectBuilder().
+		Select(foo.Column("id")).
+		From(foo).
+		WhereEquals(foo.Column("type"), 1).
+		Timeout(5 * time.Second)
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	got, err := q.BuildStatements(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []sqlb.Statement{
+		{Query: "SET LOCAL statement_timeout = 5000"},
+		{Query: `SELECT "f"."id" FROM "foo" AS "f" WHERE "f"."type" = $1`, Args: []any{1}},
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want:\n%#v\ngot:\n%#v", want, got)
+	}
+	// the setup statement is required
+	if _, _, err := q.Build(ctx); err == nil {
+		t.Error("want error for Build(), got nil")
+	}
+}
+
+func TestSelectTimeoutNested(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	bar := sqlb.NewTable("bar", "b")
+	sub := sqlb.NewSelectBuilder().
+		Select(sqlf.F("1")).
+		From(bar).
+		Where(sqlf.F("? = ?", bar.Column("foo_id"), foo.Column("id"))).
+		Timeout(time.Second)
+	q := sqlb.NewSelectBuilder().
+		Select(foo.Column("id")).
+		From(foo).
+		Where(sqlf.F("EXISTS (?)", sub)).
+		Timeout(5 * time.Second)
+	ctx := sqlb.NewContext(context.Background(), dialect.MySQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := "SELECT /*+ MAX_EXECUTION_TIME(5000) */ `f`.`id` FROM `foo` AS `f` WHERE EXISTS (SELECT 1 FROM `bar` AS `b` WHERE `b`.`foo_id` = `f`.`id`)"
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestUpdateTimeoutPostgreSQL(t *testing.T) {
+	foo := sqlb.NewTable("foo")
+	q := sqlb.NewUpdateBuilder().
+		Update("foo").
+		Set("status", "done").
+		Where(sqlf.F("? = ?", foo.Column("id"), 1)).
+		Timeout(2 * time.Second)
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	got, err := q.BuildStatements(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []sqlb.Statement{
+		{Query: "SET LOCAL statement_timeout = 2000"},
+		{Query: `UPDATE "foo" SET "status" = $1 WHERE "foo"."id" = $2`, Args: []any{"done", 1}},
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want:\n%#v\ngot:\n%#v", want, got)
+	}
+}
+
+func TestDeleteTimeoutPostgreSQL(t *testing.T) {
+	foo := sqlb.NewTable("foo")
+	q := sqlb.NewDeleteBuilder().
+		DeleteFrom("foo").
+		Where(sqlf.F("? < ?", foo.Column("created"), 100)).
+		Timeout(time.Second)
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	got, err := q.BuildStatements(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []sqlb.Statement{
+		{Query: "SET LOCAL statement_timeout = 1000"},
+		{Query: `DELETE FROM "foo" WHERE "foo"."created" < $1`, Args: []any{100}},
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want:\n%#v\ngot:\n%#v", want, got)
+	}
+}
+
+func TestInsertTimeoutPostgreSQL(t *testing.T) {
+	q := sqlb.NewInsertBuilder().
+		InsertInto("foo").
+		Columns("name").
+		Values("bar").
+		Returning("id").
+		Timeout(time.Second)
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	got, err := q.BuildStatements(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []sqlb.Statement{
+		{Query: "SET LOCAL statement_timeout = 1000"},
+		{Query: `INSERT INTO "foo" ("name") VALUES ($1) RETURNING "id"`, Args: []any{"bar"}},
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want:\n%#v\ngot:\n%#v", want, got)
+	}
+}
+
+func TestUpdateTimeoutMySQL(t *testing.T) {
+	q := sqlb.NewUpdateBuilder().
+		Update("foo").
+		Set("status", "done").
+		Timeout(time.Second)
+	ctx := sqlb.NewContext(context.Background(), dialect.MySQL{})
+	// MAX_EXECUTION_TIME applies to SELECT only
+	if _, err := q.BuildStatements(ctx); err == nil {
+		t.Error("want error, got nil")
+	}
+}
+
+func TestSelectTimeoutUnsupported(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	q := sqlb.NewSelectBuilder().
+		Select(foo.Column("id")).
+		From(foo).
+		Timeout(time.Second)
+	for _, d := range []dialect.Dialect{
+		dialect.SQLServer{},
+		dialect.Oracle{},
+		dialect.SQLite{},
+	} {
+		ctx := sqlb.NewContext(context.Background(), d)
+		if _, err := q.BuildStatements(ctx); err == nil {
+			t.Errorf("%T: want error, got nil", d)
+		}
+	}
+}
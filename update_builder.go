@@ -1,6 +1,8 @@
 package sqlb
 
 import (
+	"time"
+
 	"github.com/qjebbs/go-sqlf/v4"
 )
 
@@ -22,6 +24,9 @@ type UpdateBuilder struct {
 	order  *clauseList // order by columns, joined with comma.
 	limit  int64       // limit count
 
+	returning []sqlf.Builder // returning columns
+	timeout   time.Duration  // execution time limit
+
 	debugger
 
 	pruning bool
@@ -80,6 +85,14 @@ func (b *UpdateBuilder) Limit(limit int64) *UpdateBuilder {
 	return b
 }
 
+// Returning sets a RETURNING clause to the update statement,
+// which is built as `OUTPUT INSERTED.col` for SQLServer.
+func (b *UpdateBuilder) Returning(columns ...string) *UpdateBuilder {
+	b.resetDepTablesCache()
+	b.returning = append(b.returning, returningColumns(columns)...)
+	return b
+}
+
 func (b *UpdateBuilder) resetDepTablesCache() {
 	b.deps = nil
 }
@@ -50,6 +50,14 @@ func (b *UpdateBuilder) buildInternal(ctx Context) (string, error) {
 
 	caps := ctx.Dialect().Capabilities()
 
+	nested := isNestedStatement(ctx)
+	if !nested {
+		// the timeout applies to the top-level statement only
+		if err := checkTimeout(ctx, b.timeout); err != nil {
+			return "", err
+		}
+	}
+	ctx = contextWithNestedStatement(ctx)
 	ctx, pruning := decideContextPruning(ctx, b.pruning)
 
 	var err error
@@ -71,7 +79,7 @@ func (b *UpdateBuilder) buildInternal(ctx Context) (string, error) {
 			return "", nil
 		}
 	}
-	with, err := b.ctes.BuildRequired(ctx, myDeps.cteDeps)
+	with, err := b.ctes.BuildRequired(ctx, myDeps.cteDeps, nested)
 	if err != nil {
 		return "", err
 	}
@@ -105,6 +113,13 @@ func (b *UpdateBuilder) buildInternal(ctx Context) (string, error) {
 		return "", fmt.Errorf("no columns set for update")
 	}
 	built = append(built, sets)
+	if len(b.returning) > 0 && caps.SupportsOutputInserted {
+		output, err := buildOutput(ctx, "INSERTED", b.returning)
+		if err != nil {
+			return "", err
+		}
+		built = append(built, output)
+	}
 
 	if caps.SupportsUpdateFrom {
 		// FROM / JOINS
@@ -136,6 +151,20 @@ func (b *UpdateBuilder) buildInternal(ctx Context) (string, error) {
 	if b.limit > 0 {
 		built = append(built, fmt.Sprintf(`LIMIT %d`, b.limit))
 	}
+	if len(b.returning) > 0 {
+		switch {
+		case caps.SupportsReturning:
+			returning, err := sqlf.F("RETURNING ?", sqlf.Join(b.returning, ", ")).BuildTo(ctx)
+			if err != nil {
+				return "", fmt.Errorf("build returning clause: %w", err)
+			}
+			built = append(built, returning)
+		case caps.SupportsOutputInserted:
+			// already built
+		default:
+			return "", fmt.Errorf("returning is not supported for dialact %T", ctx.BaseDialect())
+		}
+	}
 	query := strings.TrimSpace(strings.Join(built, " "))
 	b.debugger.printIfDebug(ctx, query, ctx.Args())
 	return query, nil
@@ -148,6 +177,7 @@ func (b *UpdateBuilder) joinBuilderMeta() *fromBuilderMeta {
 			b.sets,
 			b.where,
 			b.order,
+			sqlf.Join(b.returning, ", "),
 		},
 		Distinct:   false,
 		HasGroupBy: false,
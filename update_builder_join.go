@@ -80,9 +80,21 @@ func (b *UpdateBuilder) CrossJoin(t Table) *UpdateBuilder {
 // The CTE will be automatically eliminated if all the conditions below are met:
 //   - Pruning is enabled by `b.EnableElimination()` or parent builders
 //   - The table is not referenced anywhere in the query
-func (b *UpdateBuilder) With(name Table, builder sqlf.Builder) *UpdateBuilder {
+//
+// The optional CTEOptions declares the column names and the materialization hint, e.g.:
+//
+//	b.With(t, builder, sqlb.CTEOptions{Columns: []string{"a", "b"}, Materialized: sqlb.CTEMaterialized})
+//	// "t" ("a", "b") AS MATERIALIZED (...)
+func (b *UpdateBuilder) With(name Table, builder sqlf.Builder, opts ...CTEOptions) *UpdateBuilder {
+	b.resetDepTablesCache()
+	b.ctes.With(name, builder, opts...)
+	return b
+}
+
+// WithRecursive adds a recursive common table expression, see SelectBuilder.WithRecursive.
+func (b *UpdateBuilder) WithRecursive(name Table, columns []string, anchor, recursive sqlf.Builder) *UpdateBuilder {
 	b.resetDepTablesCache()
-	b.ctes.With(name, builder)
+	b.ctes.WithRecursive(name, columns, anchor, recursive)
 	return b
 }
 
@@ -2,7 +2,6 @@ package sqlb
 
 import (
 	"github.com/qjebbs/go-sqlf/v4"
-	"github.com/qjebbs/go-sqlf/v4/util"
 )
 
 // Where add a condition.  e.g.:
@@ -91,23 +90,95 @@ func (b *UpdateBuilder) WhereNotBetween(column sqlf.Builder, start, end any) *Up
 }
 
 // WhereIn adds a where IN condition like `t.id IN (1,2,3)`
+//
+// Lists longer than the threshold are rendered with the LargeInListStrategy
+// of the dialect, see ContextWithInListThreshold.
+// An empty list is built as an always-false predicate, see ContextWithStrictInList.
 func (b *UpdateBuilder) WhereIn(column sqlf.Builder, list any) *UpdateBuilder {
 	return b.Where(
-		sqlf.F(
-			"? IN (?)",
-			column,
-			sqlf.JoinArgs(util.FlattenArgs(list), ", "),
-		),
+		newInPredicate(column, list, false),
 	)
 }
 
 // WhereNotIn adds a where NOT IN condition like `t.id NOT IN (1,2,3)`
+//
+// Lists longer than the threshold are rendered with the LargeInListStrategy
+// of the dialect, see ContextWithInListThreshold.
+// An empty list is built as an always-true predicate, see ContextWithStrictInList.
 func (b *UpdateBuilder) WhereNotIn(column sqlf.Builder, list any) *UpdateBuilder {
 	return b.Where(
-		sqlf.F(
-			"? NOT IN (?)",
-			column,
-			sqlf.JoinArgs(util.FlattenArgs(list), ", "),
-		),
+		newInPredicate(column, list, true),
+	)
+}
+
+// WhereContains adds a LIKE condition like `t.name LIKE '%foo%' ESCAPE '!'`,
+// in which the wildcards in value are escaped to be matched literally.
+func (b *UpdateBuilder) WhereContains(column sqlf.Builder, value string) *UpdateBuilder {
+	return b.Where(
+		&likePredicate{column: column, value: value, prefix: true, suffix: true},
+	)
+}
+
+// WhereStartsWith adds a LIKE condition like `t.name LIKE 'foo%' ESCAPE '!'`,
+// in which the wildcards in value are escaped to be matched literally.
+func (b *UpdateBuilder) WhereStartsWith(column sqlf.Builder, value string) *UpdateBuilder {
+	return b.Where(
+		&likePredicate{column: column, value: value, suffix: true},
+	)
+}
+
+// WhereEndsWith adds a LIKE condition like `t.name LIKE '%foo' ESCAPE '!'`,
+// in which the wildcards in value are escaped to be matched literally.
+func (b *UpdateBuilder) WhereEndsWith(column sqlf.Builder, value string) *UpdateBuilder {
+	return b.Where(
+		&likePredicate{column: column, value: value, prefix: true},
+	)
+}
+
+// WhereContainsIgnoreCase is the case-insensitive version of WhereContains,
+// which is built as `t.name ILIKE ?` if supported by the dialect,
+// otherwise `LOWER(t.name) LIKE LOWER(?)`.
+func (b *UpdateBuilder) WhereContainsIgnoreCase(column sqlf.Builder, value string) *UpdateBuilder {
+	return b.Where(
+		&likePredicate{column: column, value: value, prefix: true, suffix: true, ignoreCase: true},
+	)
+}
+
+// WhereStartsWithIgnoreCase is the case-insensitive version of WhereStartsWith,
+// which is built as `t.name ILIKE ?` if supported by the dialect,
+// otherwise `LOWER(t.name) LIKE LOWER(?)`.
+func (b *UpdateBuilder) WhereStartsWithIgnoreCase(column sqlf.Builder, value string) *UpdateBuilder {
+	return b.Where(
+		&likePredicate{column: column, value: value, suffix: true, ignoreCase: true},
+	)
+}
+
+// WhereEndsWithIgnoreCase is the case-insensitive version of WhereEndsWith,
+// which is built as `t.name ILIKE ?` if supported by the dialect,
+// otherwise `LOWER(t.name) LIKE LOWER(?)`.
+func (b *UpdateBuilder) WhereEndsWithIgnoreCase(column sqlf.Builder, value string) *UpdateBuilder {
+	return b.Where(
+		&likePredicate{column: column, value: value, prefix: true, ignoreCase: true},
+	)
+}
+
+// WhereTupleIn adds a row value IN condition like `(t.a, t.b) IN ((1,2),(3,4))`,
+// which is emulated with OR-ed AND conditions on dialects without row values.
+//
+//	b.WhereTupleIn(t.Columns("a", "b"), [][]any{{1, 2}, {3, 4}})
+func (b *UpdateBuilder) WhereTupleIn(columns []sqlf.Builder, rows [][]any) *UpdateBuilder {
+	return b.Where(
+		&tupleInPredicate{columns: columns, rows: rows},
+	)
+}
+
+// WhereTupleCompare adds a row value comparison like `(t.a, t.b) > (1,2)`,
+// which is emulated with lexicographic OR-ed AND conditions on dialects without row values.
+// The op is one of "=", "<>", "<", "<=", ">", ">=".
+//
+//	b.WhereTupleCompare(t.Columns("created_at", "id"), ">", lastCreatedAt, lastID)
+func (b *UpdateBuilder) WhereTupleCompare(columns []sqlf.Builder, op string, values ...any) *UpdateBuilder {
+	return b.Where(
+		&tupleComparePredicate{columns: columns, op: op, values: values},
 	)
 }
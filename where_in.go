@@ -0,0 +1,319 @@
+package sqlb
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/qjebbs/go-sqlb/dialect"
+	"github.com/qjebbs/go-sqlf/v4"
+	"github.com/qjebbs/go-sqlf/v4/util"
+)
+
+// ErrEmptyInList is returned in strict mode when an IN / NOT IN list is empty,
+// see ContextWithStrictInList.
+var ErrEmptyInList = errors.New("empty IN list")
+
+type inListThresholdKey struct{}
+type strictInListKey struct{}
+
+// ContextWithInListThreshold returns a new context with the IN list threshold set.
+//
+// IN lists with more than n elements are rendered with the LargeInListStrategy
+// of the dialect, e.g. `col = ANY($1)` for PostgreSQL. A non-positive n disables
+// the large list strategies, which is the default.
+//
+// The lists exceeding the MaxInListSize of the dialect always use the large list
+// strategy regardless of the threshold, e.g. chunked lists for Oracle.
+func ContextWithInListThreshold(ctx Context, n int) Context {
+	return ContextWithValue(ctx, inListThresholdKey{}, n)
+}
+
+// inListThresholdFromContext extracts the IN list threshold from context.
+func inListThresholdFromContext(ctx Context) int {
+	if v, ok := ctx.Value(inListThresholdKey{}).(int); ok {
+		return v
+	}
+	return 0
+}
+
+// ContextWithStrictInList returns a new context in which empty IN / NOT IN lists
+// fail the build with ErrEmptyInList.
+//
+// By default, an empty IN list is built as an always-false predicate,
+// and an empty NOT IN list as an always-true one, e.g. `1=0` / `1=1`.
+func ContextWithStrictInList(ctx Context) Context {
+	return ContextWithValue(ctx, strictInListKey{}, struct{}{})
+}
+
+// strictInListFromContext extracts the strict IN list flag from context.
+func strictInListFromContext(ctx Context) bool {
+	return ctx.Value(strictInListKey{}) != nil
+}
+
+var _ sqlf.Builder = (*inPredicate)(nil)
+
+// inPredicate builds `column [NOT] IN (list)` with the dialect-aware strategy.
+type inPredicate struct {
+	column sqlf.Builder
+	list   any
+	not    bool
+}
+
+func newInPredicate(column sqlf.Builder, list any, not bool) *inPredicate {
+	return &inPredicate{
+		column: column,
+		list:   list,
+		not:    not,
+	}
+}
+
+// BuildTo implements sqlf.Builder
+func (p *inPredicate) BuildTo(ctx sqlf.Context) (string, error) {
+	uCtx, err := contextUpgrade(ctx)
+	if err != nil {
+		return "", err
+	}
+	var values []any
+	if p.list != nil {
+		values = util.FlattenArgs(p.list)
+	}
+	caps := uCtx.Dialect().Capabilities()
+	if len(values) == 0 {
+		if strictInListFromContext(uCtx) {
+			return "", ErrEmptyInList
+		}
+		// x IN () is always false, x NOT IN () is always true
+		return booleanPredicate(caps, p.not), nil
+	}
+	strategy := dialect.InListExpand
+	threshold := inListThresholdFromContext(uCtx)
+	if (threshold > 0 && len(values) > threshold) ||
+		(caps.MaxInListSize > 0 && len(values) > caps.MaxInListSize) {
+		strategy = caps.LargeInListStrategy
+	}
+	switch strategy {
+	case dialect.InListArray:
+		return p.buildArray(uCtx, values)
+	case dialect.InListValues:
+		return p.buildValues(uCtx, values, caps)
+	case dialect.InListChunked:
+		return p.buildChunked(uCtx, values, caps.MaxInListSize)
+	case dialect.InListJSON:
+		return p.buildJSON(uCtx, values)
+	default:
+		return p.buildExpanded(uCtx, values)
+	}
+}
+
+func (p *inPredicate) operator() string {
+	if p.not {
+		return "NOT IN"
+	}
+	return "IN"
+}
+
+func (p *inPredicate) buildExpanded(ctx Context, values []any) (string, error) {
+	return sqlf.F(
+		"? "+p.operator()+" (?)",
+		p.column,
+		sqlf.JoinArgs(values, ", "),
+	).BuildTo(ctx)
+}
+
+func (p *inPredicate) buildArray(ctx Context, values []any) (string, error) {
+	array := &arrayArg{values: values}
+	if p.not {
+		return sqlf.F("? <> ALL(?)", p.column, array).BuildTo(ctx)
+	}
+	return sqlf.F("? = ANY(?)", p.column, array).BuildTo(ctx)
+}
+
+func (p *inPredicate) buildValues(ctx Context, values []any, caps dialect.Capabilities) (string, error) {
+	rows := make([]string, 0, len(values))
+	for _, v := range values {
+		value, ok := integerLiteral(v)
+		if !ok {
+			value = ctx.CommitArg(v)
+		}
+		rows = append(rows, caps.ValuesRowConstructor+"("+value+")")
+	}
+	list := "VALUES " + strings.Join(rows, ", ")
+	if caps.SupportsDerivedColumnList {
+		v := ctx.BaseDialect().QuoteIdentifier("v")
+		list = fmt.Sprintf(
+			"SELECT %s FROM (%s) AS %s (%s)",
+			v, list, ctx.BaseDialect().QuoteIdentifier("t"), v,
+		)
+	}
+	column, err := p.column.BuildTo(ctx)
+	if err != nil {
+		return "", err
+	}
+	return column + " " + p.operator() + " (" + list + ")", nil
+}
+
+func (p *inPredicate) buildChunked(ctx Context, values []any, size int) (string, error) {
+	if size <= 0 || len(values) <= size {
+		return p.buildExpanded(ctx, values)
+	}
+	chunks := make([]sqlf.Builder, 0, len(values)/size+1)
+	for start := 0; start < len(values); start += size {
+		end := min(start+size, len(values))
+		chunks = append(chunks, sqlf.F(
+			"? "+p.operator()+" (?)",
+			p.column,
+			sqlf.JoinArgs(values[start:end], ", "),
+		))
+	}
+	sep := " OR "
+	if p.not {
+		sep = " AND "
+	}
+	return sqlf.F("(?)", sqlf.Join(chunks, sep)).BuildTo(ctx)
+}
+
+func (p *inPredicate) buildJSON(ctx Context, values []any) (string, error) {
+	return sqlf.F(
+		"? "+p.operator()+" (SELECT value FROM OPENJSON(?))",
+		p.column,
+		&jsonArrayArg{values: values},
+	).BuildTo(ctx)
+}
+
+// booleanPredicate returns an always-true or always-false predicate for the dialect.
+func booleanPredicate(caps dialect.Capabilities, value bool) string {
+	switch {
+	case caps.SupportsBooleanLiteral && value:
+		return "TRUE"
+	case caps.SupportsBooleanLiteral:
+		return "FALSE"
+	case value:
+		return "1=1"
+	default:
+		return "1=0"
+	}
+}
+
+// integerLiteral formats integer values as SQL literals,
+// which are safe to be inlined into the query.
+//
+// Values implementing driver.Valuer are not formatted, since they are
+// converted by their Value method, which must be bound as args.
+func integerLiteral(v any) (string, bool) {
+	if _, ok := v.(driver.Valuer); ok {
+		return "", false
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10), true
+	}
+	return "", false
+}
+
+var _ driver.Valuer = (*arrayArg)(nil)
+
+// arrayArg binds a list as a single PostgreSQL array literal, e.g. '{1,2,3}',
+// whose element type is inferred by the database from the compared column.
+//
+// It's a pointer arg, since slices are not comparable and cannot be
+// deduplicated by the arg store.
+type arrayArg struct {
+	values []any
+}
+
+// Value implements driver.Valuer
+func (a *arrayArg) Value() (driver.Value, error) {
+	sb := new(strings.Builder)
+	sb.WriteRune('{')
+	for i, v := range a.values {
+		if i > 0 {
+			sb.WriteRune(',')
+		}
+		if valuer, ok := v.(driver.Valuer); ok {
+			value, err := valuer.Value()
+			if err != nil {
+				return nil, err
+			}
+			v = value
+		}
+		switch v := v.(type) {
+		case nil:
+			sb.WriteString("NULL")
+		case bool:
+			if v {
+				sb.WriteRune('t')
+			} else {
+				sb.WriteRune('f')
+			}
+		case time.Time:
+			writeArrayString(sb, v.Format(time.RFC3339Nano))
+		case []byte:
+			writeArrayString(sb, string(v))
+		case string:
+			writeArrayString(sb, v)
+		default:
+			if literal, ok := integerLiteral(v); ok {
+				sb.WriteString(literal)
+				continue
+			}
+			rv := reflect.ValueOf(v)
+			if rv.Kind() == reflect.Float32 || rv.Kind() == reflect.Float64 {
+				sb.WriteString(strconv.FormatFloat(rv.Float(), 'g', -1, 64))
+				continue
+			}
+			writeArrayString(sb, fmt.Sprint(v))
+		}
+	}
+	sb.WriteRune('}')
+	return sb.String(), nil
+}
+
+var _ driver.Valuer = (*jsonArrayArg)(nil)
+
+// jsonArrayArg binds a list as a single JSON array, e.g. '[1,"a"]', whose
+// elements are converted by the database to the type of the compared column.
+//
+// Times are encoded in RFC 3339, and []byte in base64 as encoding/json does.
+type jsonArrayArg struct {
+	values []any
+}
+
+// Value implements driver.Valuer
+func (a *jsonArrayArg) Value() (driver.Value, error) {
+	values := make([]any, len(a.values))
+	for i, v := range a.values {
+		if valuer, ok := v.(driver.Valuer); ok {
+			value, err := valuer.Value()
+			if err != nil {
+				return nil, err
+			}
+			v = value
+		}
+		values[i] = v
+	}
+	b, err := json.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func writeArrayString(sb *strings.Builder, s string) {
+	sb.WriteRune('"')
+	for _, r := range s {
+		if r == '"' || r == '\\' {
+			sb.WriteRune('\\')
+		}
+		sb.WriteRune(r)
+	}
+	sb.WriteRune('"')
+}
@@ -0,0 +1,371 @@
+package sqlb_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/qjebbs/go-sqlb"
+	"github.com/qjebbs/go-sqlb/dialect"
+	"github.com/qjebbs/go-sqlf/v4"
+)
+
+func TestWhereInExpanded(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	ids := make([]int, 150)
+	wantArgs := make([]any, len(ids))
+	placeholders := make([]string, len(ids))
+	for i := range ids {
+		ids[i] = i
+		wantArgs[i] = i
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	}
+	q := sqlb.NewSelectBuilder().
+		Select(foo.Column("*")).
+		From(foo).
+		WhereIn(foo.Column("id"), ids)
+	// the large list strategies are disabled by default
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT "f".* FROM "foo" AS "f" WHERE "f"."id" IN (` + strings.Join(placeholders, ", ") + `)`
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestWhereInUnderThreshold(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	q := sqlb.NewSelectBuilder().
+		Select(foo.Column("*")).
+		From(foo).
+		WhereIn(foo.Column("id"), []int{1, 2})
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	ctx = sqlb.ContextWithInListThreshold(ctx, 2)
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT "f".* FROM "foo" AS "f" WHERE "f"."id" IN ($1, $2)`
+	wantArgs := []any{1, 2}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestWhereInPostgreSQLArray(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	q := sqlb.NewSelectBuilder().
+		Select(foo.Column("*")).
+		From(foo).
+		WhereIn(foo.Column("id"), []int{1, 2, 3})
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	ctx = sqlb.ContextWithInListThreshold(ctx, 2)
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT "f".* FROM "foo" AS "f" WHERE "f"."id" = ANY($1)`
+	wantArgs := []any{"{1,2,3}"}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if got := driverValues(t, gotArgs); !reflect.DeepEqual(wantArgs, got) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, got)
+	}
+}
+
+func TestWhereNotInPostgreSQLArray(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	q := sqlb.NewSelectBuilder().
+		Select(foo.Column("*")).
+		From(foo).
+		WhereNotIn(foo.Column("id"), []string{"a", `"b"`, `c\d`})
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	ctx = sqlb.ContextWithInListThreshold(ctx, 2)
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT "f".* FROM "foo" AS "f" WHERE "f"."id" <> ALL($1)`
+	wantArgs := []any{`{"a","\"b\"","c\\d"}`}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if got := driverValues(t, gotArgs); !reflect.DeepEqual(wantArgs, got) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, got)
+	}
+}
+
+func TestWhereInSQLServerJSON(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	q := sqlb.NewSelectBuilder().
+		Select(foo.Column("*")).
+		From(foo).
+		WhereIn(foo.Column("code"), []any{"a", `"b"`, userID(3)})
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLServer{})
+	ctx = sqlb.ContextWithInListThreshold(ctx, 2)
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the whole list is bound as a single parameter
+	wantQuery := `SELECT [f].* FROM [foo] AS [f] WHERE [f].[code] IN (SELECT value FROM OPENJSON(@p1))`
+	wantArgs := []any{`["a","\"b\"",3]`}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if got := driverValues(t, namedValues(gotArgs)); !reflect.DeepEqual(wantArgs, got) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, got)
+	}
+}
+
+func TestWhereNotInSQLServerMaxInListSize(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	codes := make([]string, 2001)
+	for i := range codes {
+		codes[i] = fmt.Sprintf("c%d", i)
+	}
+	q := sqlb.NewSelectBuilder().
+		Select(foo.Column("*")).
+		From(foo).
+		WhereNotIn(foo.Column("code"), codes)
+	// lists over 2000 elements stay under the parameter limit of SQLServer,
+	// even if large list strategies are disabled
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLServer{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT [f].* FROM [foo] AS [f] WHERE [f].[code] NOT IN (SELECT value FROM OPENJSON(@p1))`
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if len(gotArgs) != 1 {
+		t.Errorf("want 1 arg, got %d", len(gotArgs))
+	}
+}
+
+func TestWhereInMySQLValues(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	q := sqlb.NewSelectBuilder().
+		Select(foo.Column("*")).
+		From(foo).
+		WhereIn(foo.Column("id"), []any{1, 2, userID(3)})
+	ctx := sqlb.NewContext(context.Background(), dialect.MySQL{})
+	ctx = sqlb.ContextWithInListThreshold(ctx, 2)
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// values implementing driver.Valuer are bound rather than inlined
+	wantQuery := "SELECT `f`.* FROM `foo` AS `f` WHERE `f`.`id` IN (SELECT `v` FROM (VALUES ROW(1), ROW(2), ROW(?)) AS `t` (`v`))"
+	wantArgs := []any{userID(3)}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestWhereNotInSQLiteValues(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	q := sqlb.NewSelectBuilder().
+		Select(foo.Column("*")).
+		From(foo).
+		WhereNotIn(foo.Column("id"), []int{1, 2, 3})
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLite{})
+	ctx = sqlb.ContextWithInListThreshold(ctx, 2)
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT "f".* FROM "foo" AS "f" WHERE "f"."id" NOT IN (VALUES (1), (2), (3))`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestWhereInOracleChunks(t *testing.T) {
+	foo := sqlb.NewTable("foo")
+	ids := make([]int, 1001)
+	wantArgs := make([]any, len(ids))
+	placeholders := make([]string, len(ids))
+	for i := range ids {
+		ids[i] = i
+		wantArgs[i] = i
+		placeholders[i] = fmt.Sprintf(":%d", i+1)
+	}
+	q := sqlb.NewDeleteBuilder().
+		DeleteFrom(foo.Name).
+		WhereIn(foo.Column("id"), ids)
+	// chunking is enforced by MaxInListSize even if large list strategies are disabled
+	ctx := sqlb.NewContext(context.Background(), dialect.Oracle{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `DELETE FROM "foo" WHERE ("foo"."id" IN (` + strings.Join(placeholders[:1000], ", ") +
+		`) OR "foo"."id" IN (` + placeholders[1000] + `))`
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestWhereInEmptyList(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	q := sqlb.NewSelectBuilder().
+		Select(foo.Column("*")).
+		From(foo).
+		WhereIn(foo.Column("id"), []int{}).
+		WhereNotIn(foo.Column("type"), nil)
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT "f".* FROM "foo" AS "f" WHERE FALSE AND TRUE`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+	ctx = sqlb.ContextWithStrictInList(ctx)
+	if _, _, err := q.Build(ctx); !errors.Is(err, sqlb.ErrEmptyInList) {
+		t.Errorf("got error %v, want %v", err, sqlb.ErrEmptyInList)
+	}
+}
+
+func TestHavingInEmptyList(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	q := sqlb.NewSelectBuilder().
+		Select(foo.Column("type")).
+		From(foo).
+		GroupBy(foo.Column("type")).
+		HavingIn(foo.Column("type"), []string{}).
+		HavingNotIn(foo.Column("type"), []string{})
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLServer{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT [f].[type] FROM [foo] AS [f] GROUP BY [f].[type] HAVING 1=0 AND 1=1`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+	ctx = sqlb.ContextWithStrictInList(ctx)
+	if _, _, err := q.Build(ctx); !errors.Is(err, sqlb.ErrEmptyInList) {
+		t.Errorf("got error %v, want %v", err, sqlb.ErrEmptyInList)
+	}
+}
+
+func TestUpdateWhereInEmptyList(t *testing.T) {
+	q := sqlb.NewUpdateBuilder().
+		Update("foo").
+		Set("a", 1).
+		WhereIn(sqlf.Identifier("id"), []int{})
+	ctx := sqlb.NewContext(context.Background(), dialect.Oracle{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `UPDATE "foo" SET "a" = :1 WHERE 1=0`
+	wantArgs := []any{1}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+	ctx = sqlb.ContextWithStrictInList(ctx)
+	if _, _, err := q.Build(ctx); !errors.Is(err, sqlb.ErrEmptyInList) {
+		t.Errorf("got error %v, want %v", err, sqlb.ErrEmptyInList)
+	}
+}
+
+func TestDeleteWhereNotInEmptyList(t *testing.T) {
+	q := sqlb.NewDeleteBuilder().
+		DeleteFrom("foo").
+		WhereNotIn(sqlf.Identifier("id"), []int{})
+	ctx := sqlb.NewContext(context.Background(), dialect.MySQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := "DELETE FROM `foo` WHERE TRUE"
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+	ctx = sqlb.ContextWithStrictInList(ctx)
+	if _, _, err := q.Build(ctx); !errors.Is(err, sqlb.ErrEmptyInList) {
+		t.Errorf("got error %v, want %v", err, sqlb.ErrEmptyInList)
+	}
+}
+
+// userID is an integer type converted by driver.Valuer.
+type userID int
+
+// Value implements driver.Valuer
+func (id userID) Value() (driver.Value, error) {
+	return int64(id), nil
+}
+
+// namedValues returns the values of sql.NamedArg args.
+func namedValues(args []any) []any {
+	values := make([]any, len(args))
+	for i, arg := range args {
+		values[i] = arg
+		if named, ok := arg.(sql.NamedArg); ok {
+			values[i] = named.Value
+		}
+	}
+	return values
+}
+
+// driverValues converts the args with driver.Valuer to their driver values.
+func driverValues(t *testing.T, args []any) []any {
+	t.Helper()
+	values := make([]any, len(args))
+	for i, arg := range args {
+		values[i] = arg
+		if valuer, ok := arg.(driver.Valuer); ok {
+			v, err := valuer.Value()
+			if err != nil {
+				t.Fatal(err)
+			}
+			values[i] = v
+		}
+	}
+	return values
+}
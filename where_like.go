@@ -0,0 +1,58 @@
+package sqlb
+
+import (
+	"strings"
+
+	"github.com/qjebbs/go-sqlf/v4"
+)
+
+// likeEscape is the escape character used in LIKE patterns.
+// It's not a backslash, which is also an escape character
+// in MySQL string literals.
+const likeEscape = '!'
+
+var _ sqlf.Builder = (*likePredicate)(nil)
+
+// likePredicate builds `column LIKE pattern ESCAPE '!'` with
+// the user input escaped and wildcards added.
+type likePredicate struct {
+	column     sqlf.Builder
+	value      string
+	prefix     bool // add wildcard before the value
+	suffix     bool // add wildcard after the value
+	ignoreCase bool
+}
+
+// BuildTo implements sqlf.Builder
+func (p *likePredicate) BuildTo(ctx sqlf.Context) (string, error) {
+	uCtx, err := contextUpgrade(ctx)
+	if err != nil {
+		return "", err
+	}
+	caps := uCtx.Dialect().Capabilities()
+	sb := new(strings.Builder)
+	if p.prefix {
+		sb.WriteRune('%')
+	}
+	for _, r := range p.value {
+		switch {
+		case r == likeEscape, r == '%', r == '_':
+			sb.WriteRune(likeEscape)
+		case r == '[' && caps.SupportsLikeCharClass:
+			sb.WriteRune(likeEscape)
+		}
+		sb.WriteRune(r)
+	}
+	if p.suffix {
+		sb.WriteRune('%')
+	}
+	pattern := sb.String()
+	switch {
+	case !p.ignoreCase:
+		return sqlf.F("? LIKE ? ESCAPE '!'", p.column, pattern).BuildTo(uCtx)
+	case caps.SupportsILike:
+		return sqlf.F("? ILIKE ? ESCAPE '!'", p.column, pattern).BuildTo(uCtx)
+	default:
+		return sqlf.F("LOWER(?) LIKE LOWER(?) ESCAPE '!'", p.column, pattern).BuildTo(uCtx)
+	}
+}
@@ -0,0 +1,201 @@
+package sqlb
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/qjebbs/go-sqlb/dialect"
+	"github.com/qjebbs/go-sqlf/v4"
+)
+
+var _ sqlf.Builder = (*matchPredicate)(nil)
+
+// matchPredicate builds the full-text search predicate for columns,
+// or the relevance score of the search if score is true.
+type matchPredicate struct {
+	columns []sqlf.Builder
+	query   string
+	boolean bool
+	score   bool
+	key     sqlf.Builder
+}
+
+// MatchScore returns the relevance score of the full-text search of query
+// against columns, where the higher scores indicate the more relevant rows.
+// See SelectBuilder.WhereMatches for the rendering per dialect:
+//
+//	b.Select(t.Column("*"), sqlb.MatchScore(columns, query)).
+//		From(t).
+//		WhereMatches(columns, query).
+//		OrderBy(sqlf.F("? DESC", sqlb.MatchScore(columns, query)))
+//
+// The score is not supported by SQLServer, use MatchScoreByKey instead.
+func MatchScore(columns []sqlf.Builder, query string) sqlf.Builder {
+	return &matchPredicate{
+		columns: columns,
+		query:   query,
+		score:   true,
+	}
+}
+
+// MatchScoreByKey is like MatchScore, but also takes the key column of the
+// full-text index, which SQLServer requires to look up the rank of the row
+// in FREETEXTTABLE, e.g.:
+//
+//	sqlb.MatchScoreByKey(t.Column("id"), t.Columns("title", "body"), query)
+//	// COALESCE((SELECT [ft].[RANK] FROM FREETEXTTABLE([docs], ([title], [body]), @p1) AS [ft]
+//	//   WHERE [ft].[KEY] = [t].[id]), 0)
+//
+// The key is ignored by other dialects.
+func MatchScoreByKey(key sqlf.Builder, columns []sqlf.Builder, query string) sqlf.Builder {
+	return &matchPredicate{
+		columns: columns,
+		query:   query,
+		score:   true,
+		key:     key,
+	}
+}
+
+// BuildTo implements sqlf.Builder
+func (p *matchPredicate) BuildTo(ctx sqlf.Context) (string, error) {
+	uCtx, err := contextUpgrade(ctx)
+	if err != nil {
+		return "", err
+	}
+	if len(p.columns) == 0 {
+		return "", fmt.Errorf("full-text search: no columns")
+	}
+	if !p.score && strings.TrimSpace(p.query) == "" {
+		// an empty query matches nothing
+		return booleanPredicate(uCtx.Dialect().Capabilities(), false), nil
+	}
+	columns := sqlf.Join(p.columns, ", ")
+	switch d := uCtx.Dialect(); d.Capabilities().FullTextSearch {
+	case dialect.FullTextSearchTSVector:
+		document := sqlf.F("to_tsvector(?)", p.columns[0])
+		if len(p.columns) > 1 {
+			document = sqlf.F("to_tsvector(concat_ws(' ', ?))", columns)
+		}
+		query := sqlf.F("plainto_tsquery(?)", p.query)
+		if p.boolean {
+			query = sqlf.F("to_tsquery(?)", p.query)
+		}
+		if p.score {
+			return sqlf.F("ts_rank(?, ?)", document, query).BuildTo(uCtx)
+		}
+		return sqlf.F("? @@ ?", document, query).BuildTo(uCtx)
+	case dialect.FullTextSearchMatchAgainst:
+		mode := "NATURAL LANGUAGE"
+		if p.boolean {
+			mode = "BOOLEAN"
+		}
+		// the score is the same expression in the select list
+		return sqlf.F("MATCH (?) AGAINST (? IN "+mode+" MODE)", columns, p.query).BuildTo(uCtx)
+	case dialect.FullTextSearchContains:
+		if p.score {
+			return p.buildRankTable(uCtx)
+		}
+		if len(p.columns) > 1 {
+			columns = sqlf.F("(?)", columns)
+		}
+		if p.boolean {
+			return sqlf.F("CONTAINS(?, ?)", columns, p.query).BuildTo(uCtx)
+		}
+		return sqlf.F("FREETEXT(?, ?)", columns, p.query).BuildTo(uCtx)
+	case dialect.FullTextSearchFTS5:
+		return p.buildFTS5(uCtx)
+	default:
+		return "", fmt.Errorf("full-text search: unsupported dialect %T", d)
+	}
+}
+
+// buildRankTable builds the score by looking up the rank of the row in
+// the FREETEXTTABLE of the table, e.g.:
+//
+//	COALESCE((SELECT [ft].[RANK] FROM FREETEXTTABLE([docs], [title], @p1) AS [ft] WHERE [ft].[KEY] = [d].[id]), 0)
+//
+// Rows not matched have no rank, which are scored 0.
+func (p *matchPredicate) buildRankTable(ctx Context) (string, error) {
+	if p.key == nil {
+		return "", fmt.Errorf("full-text search: score requires the key column for %T, see MatchScoreByKey", ctx.Dialect())
+	}
+	table, err := p.table(ctx)
+	if err != nil {
+		return "", err
+	}
+	// the function takes the column names of the table without the prefix
+	prefix := ctx.BaseDialect().QuoteIdentifier(table.AppliedName()) + "."
+	names := make([]string, 0, len(p.columns))
+	for _, column := range p.columns {
+		name, err := column.BuildTo(ContextWithNewArgStore(ctx))
+		if err != nil {
+			return "", err
+		}
+		if !strings.HasPrefix(name, prefix) {
+			return "", fmt.Errorf("full-text search: %s is not a column of table %s", name, table.Name)
+		}
+		names = append(names, strings.TrimPrefix(name, prefix))
+	}
+	columns := strings.Join(names, ", ")
+	if len(names) > 1 {
+		columns = "(" + columns + ")"
+	}
+	ft := sqlf.Identifier("ft")
+	return sqlf.F(
+		"COALESCE((SELECT ?.? FROM FREETEXTTABLE(?, "+columns+", ?) AS ? WHERE ?.? = ?), 0)",
+		ft, sqlf.Identifier("RANK"), sqlf.Identifier(table.Name), p.query,
+		ft, ft, sqlf.Identifier("KEY"), p.key,
+	).BuildTo(ctx)
+}
+
+// table returns the only table of the columns.
+func (p *matchPredicate) table(ctx Context) (Table, error) {
+	// use a separate context to avoid polluting args
+	deps := newDependencies()
+	if _, err := sqlf.Join(p.columns, ", ").BuildTo(
+		contextWithDependencies(ContextWithNewArgStore(ctx), deps),
+	); err != nil {
+		return Table{}, err
+	}
+	if len(deps.Tables) != 1 {
+		return Table{}, fmt.Errorf("full-text search: columns must be of one table, got %d tables", len(deps.Tables))
+	}
+	var table Table
+	for t := range deps.Tables {
+		table = t
+	}
+	return table, nil
+}
+
+// buildFTS5 builds the predicate against the FTS5 virtual table of the columns,
+// which is referenced by the hidden column of the table name, e.g.:
+//
+//	"d"."docs" MATCH '"foo" "bar"'
+//
+// Since FTS5 accepts only one MATCH constraint over a table for the index,
+// a single column is matched by itself, while multiple columns are matched
+// by all the indexed columns of the table.
+func (p *matchPredicate) buildFTS5(ctx Context) (string, error) {
+	table, err := p.table(ctx)
+	if err != nil {
+		return "", err
+	}
+	hidden := sqlf.F("?.?", table, sqlf.Identifier(table.Name))
+	if p.score {
+		// bm25() returns the lower values for the more relevant rows
+		return sqlf.F("-bm25(?)", hidden).BuildTo(ctx)
+	}
+	var target sqlf.Builder = hidden
+	if len(p.columns) == 1 {
+		target = p.columns[0]
+	}
+	if p.boolean {
+		return sqlf.F("? MATCH ?", target, p.query).BuildTo(ctx)
+	}
+	// quote the terms to match them literally, instead of the FTS5 query syntax
+	terms := strings.Fields(p.query)
+	for i, term := range terms {
+		terms[i] = `"` + strings.ReplaceAll(term, `"`, `""`) + `"`
+	}
+	return sqlf.F("? MATCH ?", target, strings.Join(terms, " ")).BuildTo(ctx)
+}
@@ -0,0 +1,187 @@
+package sqlb_test
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/qjebbs/go-sqlb"
+	"github.com/qjebbs/go-sqlb/dialect"
+	"github.com/qjebbs/go-sqlf/v4"
+)
+
+func TestWhereMatchesPostgreSQL(t *testing.T) {
+	docs := sqlb.NewTable("docs", "d")
+	columns := docs.Columns("title", "body")
+	q := sqlb.NewSelectBuilder().
+		Select(docs.Column("id"), sqlb.MatchScore(columns, "hello world")).
+		From(docs).
+		WhereMatches(columns, "hello world")
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT "d"."id", ts_rank(to_tsvector(concat_ws(' ', "d"."title", "d"."body")), plainto_tsquery($1)) FROM "docs" AS "d" WHERE to_tsvector(concat_ws(' ', "d"."title", "d"."body")) @@ plainto_tsquery($1)`
+	wantArgs := []any{"hello world"}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestWhereMatchesMySQL(t *testing.T) {
+	docs := sqlb.NewTable("docs", "d")
+	columns := docs.Columns("title", "body")
+	q := sqlb.NewSelectBuilder().
+		Select(docs.Column("id"), sqlb.MatchScore(columns, "hello")).
+		From(docs).
+		WhereMatches(columns, "hello")
+	ctx := sqlb.NewContext(context.Background(), dialect.MySQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := "SELECT `d`.`id`, MATCH (`d`.`title`, `d`.`body`) AGAINST (? IN NATURAL LANGUAGE MODE) FROM `docs` AS `d` WHERE MATCH (`d`.`title`, `d`.`body`) AGAINST (? IN NATURAL LANGUAGE MODE)"
+	wantArgs := []any{"hello", "hello"}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestWhereMatchesSQLite(t *testing.T) {
+	docs := sqlb.NewTable("docs", "d")
+	columns := docs.Columns("title")
+	q := sqlb.NewSelectBuilder().
+		Select(docs.Column("id"), sqlb.MatchScore(columns, `say "hi"`)).
+		From(docs).
+		WhereMatches(columns, `say "hi"`)
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLite{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT "d"."id", -bm25("d"."docs") FROM "docs" AS "d" WHERE "d"."title" MATCH ?`
+	wantArgs := []any{`"say" """hi"""`}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestWhereMatchesSQLiteMultipleColumns(t *testing.T) {
+	docs := sqlb.NewTable("docs", "d")
+	columns := docs.Columns("title", "body")
+	q := sqlb.NewSelectBuilder().
+		Select(docs.Column("id"), sqlb.MatchScore(columns, "hello")).
+		From(docs).
+		WhereMatches(columns, "hello")
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLite{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT "d"."id", -bm25("d"."docs") FROM "docs" AS "d" WHERE "d"."docs" MATCH ?`
+	wantArgs := []any{`"hello"`}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestWhereMatchesSQLServer(t *testing.T) {
+	docs := sqlb.NewTable("docs", "d")
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLServer{})
+	b := sqlb.NewSelectBuilder().
+		Select(docs.Column("id")).
+		From(docs).
+		WhereMatches(docs.Columns("title", "body"), "hello")
+	gotQuery, gotArgs, err := b.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT [d].[id] FROM [docs] AS [d] WHERE FREETEXT(([d].[title], [d].[body]), @p1)`
+	wantArgs := []any{sql.Named("p1", "hello")}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+	_, _, err = sqlf.Build(ctx, sqlb.MatchScore(docs.Columns("title"), "hello"))
+	if err == nil {
+		t.Error("want error for score without key on SQLServer, got nil")
+	}
+}
+
+func TestMatchScoreByKeySQLServer(t *testing.T) {
+	docs := sqlb.NewTable("docs", "d")
+	q := sqlb.NewSelectBuilder().
+		Select(docs.Column("id"), sqlb.MatchScoreByKey(docs.Column("id"), docs.Columns("title", "body"), "hello")).
+		From(docs)
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLServer{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT [d].[id], COALESCE((SELECT [ft].[RANK] FROM FREETEXTTABLE([docs], ([title], [body]), @p1) AS [ft] WHERE [ft].[KEY] = [d].[id]), 0) FROM [docs] AS [d]`
+	wantArgs := []any{sql.Named("p1", "hello")}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestWhereMatchesBooleanPostgreSQL(t *testing.T) {
+	docs := sqlb.NewTable("docs", "d")
+	q := sqlb.NewSelectBuilder().
+		Select(docs.Column("id")).
+		From(docs).
+		WhereMatchesBoolean(docs.Columns("title"), "hello & !world")
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT "d"."id" FROM "docs" AS "d" WHERE to_tsvector("d"."title") @@ to_tsquery($1)`
+	wantArgs := []any{"hello & !world"}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestWhereMatchesBooleanSQLServer(t *testing.T) {
+	docs := sqlb.NewTable("docs", "d")
+	q := sqlb.NewSelectBuilder().
+		Select(docs.Column("id")).
+		From(docs).
+		WhereMatchesBoolean(docs.Columns("title", "body"), `"hello*" AND world`)
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLServer{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT [d].[id] FROM [docs] AS [d] WHERE CONTAINS(([d].[title], [d].[body]), @p1)`
+	wantArgs := []any{sql.Named("p1", `"hello*" AND world`)}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
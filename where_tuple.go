@@ -0,0 +1,135 @@
+package sqlb
+
+import (
+	"fmt"
+
+	"github.com/qjebbs/go-sqlf/v4"
+)
+
+var _ sqlf.Builder = (*tupleInPredicate)(nil)
+var _ sqlf.Builder = (*tupleComparePredicate)(nil)
+
+// tupleInPredicate builds `(a, b) IN ((1, 2), (3, 4))`,
+// or the OR-ed AND conditions if row values are not supported.
+type tupleInPredicate struct {
+	columns []sqlf.Builder
+	rows    [][]any
+}
+
+// BuildTo implements sqlf.Builder
+func (p *tupleInPredicate) BuildTo(ctx sqlf.Context) (string, error) {
+	uCtx, err := contextUpgrade(ctx)
+	if err != nil {
+		return "", err
+	}
+	if len(p.columns) == 0 {
+		return "", fmt.Errorf("tuple IN: no columns")
+	}
+	for i, row := range p.rows {
+		if len(row) != len(p.columns) {
+			return "", fmt.Errorf("tuple IN: row #%d has %d values, want %d", i+1, len(row), len(p.columns))
+		}
+	}
+	caps := uCtx.Dialect().Capabilities()
+	if len(p.rows) == 0 {
+		if strictInListFromContext(uCtx) {
+			return "", ErrEmptyInList
+		}
+		return booleanPredicate(caps, false), nil
+	}
+	if caps.SupportsTupleIn {
+		rows := make([]sqlf.Builder, 0, len(p.rows))
+		for _, row := range p.rows {
+			rows = append(rows, sqlf.F("(?)", sqlf.JoinMixed(row, ", ")))
+		}
+		return sqlf.F(
+			"(?) IN (?)",
+			sqlf.Join(p.columns, ", "),
+			sqlf.Join(rows, ", "),
+		).BuildTo(uCtx)
+	}
+	rows := make([]sqlf.Builder, 0, len(p.rows))
+	for _, row := range p.rows {
+		conds := make([]sqlf.Builder, 0, len(p.columns))
+		for i, column := range p.columns {
+			conds = append(conds, sqlf.F("? = ?", column, row[i]))
+		}
+		rows = append(rows, sqlf.F("(?)", sqlf.Join(conds, " AND ")))
+	}
+	return sqlf.F("(?)", sqlf.Join(rows, " OR ")).BuildTo(uCtx)
+}
+
+// tupleComparePredicate builds `(a, b) > (1, 2)`,
+// or the lexicographic OR-ed AND conditions if row values are not supported.
+type tupleComparePredicate struct {
+	columns []sqlf.Builder
+	op      string
+	values  []any
+}
+
+// BuildTo implements sqlf.Builder
+func (p *tupleComparePredicate) BuildTo(ctx sqlf.Context) (string, error) {
+	uCtx, err := contextUpgrade(ctx)
+	if err != nil {
+		return "", err
+	}
+	if len(p.columns) == 0 {
+		return "", fmt.Errorf("tuple comparison: no columns")
+	}
+	if len(p.values) != len(p.columns) {
+		return "", fmt.Errorf("tuple comparison: got %d values, want %d", len(p.values), len(p.columns))
+	}
+	var strict string
+	switch p.op {
+	case "=", "<>", "<", ">":
+		strict = p.op
+	case "<=":
+		strict = "<"
+	case ">=":
+		strict = ">"
+	default:
+		return "", fmt.Errorf("tuple comparison: unsupported operator %q", p.op)
+	}
+	if uCtx.Dialect().Capabilities().SupportsTupleComparison {
+		return sqlf.F(
+			"(?) "+p.op+" (?)",
+			sqlf.Join(p.columns, ", "),
+			sqlf.JoinMixed(p.values, ", "),
+		).BuildTo(uCtx)
+	}
+	switch p.op {
+	case "=":
+		return p.joinConditions(uCtx, "=", " AND ")
+	case "<>":
+		return p.joinConditions(uCtx, "<>", " OR ")
+	}
+	// (a, b, c) > (1, 2, 3) is equivalent to:
+	// (a > 1 OR (a = 1 AND b > 2) OR (a = 1 AND b = 2 AND c > 3))
+	// and only the last comparison respects the non-strict operators.
+	terms := make([]sqlf.Builder, 0, len(p.columns))
+	for i := range p.columns {
+		op := strict
+		if i == len(p.columns)-1 {
+			op = p.op
+		}
+		conds := make([]sqlf.Builder, 0, i+1)
+		for j := range i {
+			conds = append(conds, sqlf.F("? = ?", p.columns[j], p.values[j]))
+		}
+		conds = append(conds, sqlf.F("? "+op+" ?", p.columns[i], p.values[i]))
+		if len(conds) == 1 {
+			terms = append(terms, conds[0])
+			continue
+		}
+		terms = append(terms, sqlf.F("(?)", sqlf.Join(conds, " AND ")))
+	}
+	return sqlf.F("(?)", sqlf.Join(terms, " OR ")).BuildTo(uCtx)
+}
+
+func (p *tupleComparePredicate) joinConditions(ctx Context, op, sep string) (string, error) {
+	conds := make([]sqlf.Builder, 0, len(p.columns))
+	for i, column := range p.columns {
+		conds = append(conds, sqlf.F("? "+op+" ?", column, p.values[i]))
+	}
+	return sqlf.F("(?)", sqlf.Join(conds, sep)).BuildTo(ctx)
+}
@@ -0,0 +1,155 @@
+package sqlb_test
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/qjebbs/go-sqlb"
+	"github.com/qjebbs/go-sqlb/dialect"
+)
+
+func TestWhereTupleIn(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	q := sqlb.NewSelectBuilder().
+		Select(foo.Column("*")).
+		From(foo).
+		WhereTupleIn(foo.Columns("a", "b"), [][]any{{1, 2}, {3, 4}})
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT "f".* FROM "foo" AS "f" WHERE ("f"."a", "f"."b") IN (($1, $2), ($3, $4))`
+	wantArgs := []any{1, 2, 3, 4}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestWhereTupleInEmulated(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	q := sqlb.NewSelectBuilder().
+		Select(foo.Column("*")).
+		From(foo).
+		WhereTupleIn(foo.Columns("a", "b"), [][]any{{1, 2}, {3, 4}})
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLServer{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT [f].* FROM [foo] AS [f] WHERE (([f].[a] = @p1 AND [f].[b] = @p2) OR ([f].[a] = @p3 AND [f].[b] = @p4))`
+	wantArgs := []any{sql.Named("p1", 1), sql.Named("p2", 2), sql.Named("p3", 3), sql.Named("p4", 4)}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestWhereTupleInEmpty(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	q := sqlb.NewSelectBuilder().
+		Select(foo.Column("*")).
+		From(foo).
+		WhereTupleIn(foo.Columns("a", "b"), nil)
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLite{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT "f".* FROM "foo" AS "f" WHERE FALSE`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestWhereTupleCompare(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	q := sqlb.NewSelectBuilder().
+		Select(foo.Column("*")).
+		From(foo).
+		WhereTupleCompare(foo.Columns("a", "b"), ">", 1, 2)
+	ctx := sqlb.NewContext(context.Background(), dialect.MySQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := "SELECT `f`.* FROM `foo` AS `f` WHERE (`f`.`a`, `f`.`b`) > (?, ?)"
+	wantArgs := []any{1, 2}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestWhereTupleCompareEmulated(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	q := sqlb.NewSelectBuilder().
+		Select(foo.Column("*")).
+		From(foo).
+		WhereTupleCompare(foo.Columns("a", "b", "c"), ">=", 1, 2, 3)
+	ctx := sqlb.NewContext(context.Background(), dialect.Oracle{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT "f".* FROM "foo" AS "f" WHERE ("f"."a" > :1 OR ("f"."a" = :1 AND "f"."b" > :2) OR ("f"."a" = :1 AND "f"."b" = :2 AND "f"."c" >= :3))`
+	wantArgs := []any{1, 2, 3}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestWhereTupleCompareEmulatedNotEqual(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	q := sqlb.NewSelectBuilder().
+		Select(foo.Column("*")).
+		From(foo).
+		WhereTupleCompare(foo.Columns("a", "b"), "<>", 1, 2)
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLServer{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT [f].* FROM [foo] AS [f] WHERE ([f].[a] <> @p1 OR [f].[b] <> @p2)`
+	wantArgs := []any{sql.Named("p1", 1), sql.Named("p2", 2)}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestWhereTupleErrors(t *testing.T) {
+	foo := sqlb.NewTable("foo", "f")
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	builders := []sqlb.Builder{
+		sqlb.NewDeleteBuilder().DeleteFrom("foo").
+			WhereTupleIn(foo.Columns("a", "b"), [][]any{{1}}),
+		sqlb.NewUpdateBuilder().Update("foo").Set("a", 1).
+			WhereTupleCompare(foo.Columns("a", "b"), ">", 1),
+		sqlb.NewSelectBuilder().Select(foo.Column("*")).From(foo).
+			WhereTupleCompare(foo.Columns("a", "b"), "LIKE", 1, 2),
+	}
+	for i, b := range builders {
+		if _, _, err := b.Build(ctx); err == nil {
+			t.Errorf("#%d: want error, got nil", i)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package sqlb
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/qjebbs/go-sqlf/v4"
+)
+
+var _ sqlf.Builder = (*WindowBuilder)(nil)
+
+// WindowBuilder builds a window function call, e.g.:
+//
+//	SUM("o"."amount") OVER (PARTITION BY "o"."user_id" ORDER BY "o"."id" ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW)
+//
+// Column references in the window are reported to the dependency tracking,
+// so that the JOINs used only in windows are not eliminated.
+type WindowBuilder struct {
+	fn         sqlf.Builder
+	base       string         // name of the referenced window
+	partitions []sqlf.Builder // partition by columns
+	orders     []sqlf.Builder // order by columns
+	frame      *windowFrame
+}
+
+// windowFrame is the frame clause of a window, e.g. `ROWS BETWEEN ... AND ...`.
+type windowFrame struct {
+	unit       string // ROWS, RANGE or GROUPS
+	start, end FrameBound
+}
+
+// FrameBound is the start or end bound of a window frame.
+type FrameBound struct {
+	keyword string // e.g. PRECEDING, CURRENT ROW
+	offset  any    // nil, integer or sqlf.Builder
+}
+
+// Frame bounds without offsets.
+var (
+	FrameUnboundedPreceding = FrameBound{keyword: "UNBOUNDED PRECEDING"}
+	FrameCurrentRow         = FrameBound{keyword: "CURRENT ROW"}
+	FrameUnboundedFollowing = FrameBound{keyword: "UNBOUNDED FOLLOWING"}
+)
+
+// FramePreceding returns the frame bound `offset PRECEDING`, in which offset is
+// an integer rendered as literal, or a sqlf.Builder, e.g. `INTERVAL '7' DAY`.
+func FramePreceding(offset any) FrameBound {
+	return FrameBound{keyword: "PRECEDING", offset: offset}
+}
+
+// FrameFollowing returns the frame bound `offset FOLLOWING`, see FramePreceding.
+func FrameFollowing(offset any) FrameBound {
+	return FrameBound{keyword: "FOLLOWING", offset: offset}
+}
+
+// Over returns a builder of the window function call, e.g.:
+//
+//	o := sqlb.NewTable("orders", "o")
+//	sqlb.Over(sqlf.F("SUM(?)", o.Column("amount"))).
+//		PartitionBy(o.Column("user_id")).
+//		OrderBy(o.Column("id")).
+//		Rows(sqlb.FrameUnboundedPreceding, sqlb.FrameCurrentRow)
+func Over(fn sqlf.Builder) *WindowBuilder {
+	return &WindowBuilder{fn: fn}
+}
+
+// NewWindow returns a window specification, which is used with SelectBuilder.Window.
+func NewWindow() *WindowBuilder {
+	return &WindowBuilder{}
+}
+
+// Window bases the window on a named window, which is defined by SelectBuilder.Window.
+//
+//	sqlb.Over(sqlf.F("RANK()")).Window("w")
+//	// RANK() OVER "w"
+func (b *WindowBuilder) Window(name string) *WindowBuilder {
+	b.base = name
+	return b
+}
+
+// PartitionBy appends the PARTITION BY columns.
+func (b *WindowBuilder) PartitionBy(columns ...sqlf.Builder) *WindowBuilder {
+	b.partitions = append(b.partitions, columns...)
+	return b
+}
+
+// OrderBy appends the ORDER BY columns.
+//
+//	sqlb.Over(sqlf.F("ROW_NUMBER()")).OrderBy(sqlf.F("? DESC", foo.Column("bar")))
+func (b *WindowBuilder) OrderBy(order ...sqlf.Builder) *WindowBuilder {
+	b.orders = append(b.orders, order...)
+	return b
+}
+
+// Rows sets the frame `ROWS BETWEEN start AND end`.
+func (b *WindowBuilder) Rows(start, end FrameBound) *WindowBuilder {
+	b.frame = &windowFrame{unit: "ROWS", start: start, end: end}
+	return b
+}
+
+// Range sets the frame `RANGE BETWEEN start AND end`.
+func (b *WindowBuilder) Range(start, end FrameBound) *WindowBuilder {
+	b.frame = &windowFrame{unit: "RANGE", start: start, end: end}
+	return b
+}
+
+// Groups sets the frame `GROUPS BETWEEN start AND end`,
+// which is not supported by MySQL, SQLServer and Oracle.
+func (b *WindowBuilder) Groups(start, end FrameBound) *WindowBuilder {
+	b.frame = &windowFrame{unit: "GROUPS", start: start, end: end}
+	return b
+}
+
+// BuildTo implements sqlf.Builder
+func (b *WindowBuilder) BuildTo(ctx sqlf.Context) (string, error) {
+	if b.fn == nil {
+		return "", fmt.Errorf("window: no function specified, use sqlb.Over()")
+	}
+	fn, err := b.fn.BuildTo(ctx)
+	if err != nil {
+		return "", err
+	}
+	if b.base != "" && len(b.partitions) == 0 && len(b.orders) == 0 && b.frame == nil {
+		name, err := sqlf.Identifier(b.base).BuildTo(ctx)
+		if err != nil {
+			return "", err
+		}
+		return fn + " OVER " + name, nil
+	}
+	spec, err := b.buildSpec(ctx)
+	if err != nil {
+		return "", err
+	}
+	return fn + " OVER (" + spec + ")", nil
+}
+
+// buildSpec builds the window specification inside the parentheses.
+func (b *WindowBuilder) buildSpec(ctx sqlf.Context) (string, error) {
+	uCtx, err := contextUpgrade(ctx)
+	if err != nil {
+		return "", err
+	}
+	built := make([]string, 0, 4)
+	if b.base != "" {
+		name, err := sqlf.Identifier(b.base).BuildTo(ctx)
+		if err != nil {
+			return "", err
+		}
+		built = append(built, name)
+	}
+	if len(b.partitions) > 0 {
+		partitions, err := sqlf.Prefix("PARTITION BY", sqlf.Join(b.partitions, ", ")).BuildTo(ctx)
+		if err != nil {
+			return "", err
+		}
+		built = append(built, partitions)
+	}
+	if len(b.orders) > 0 {
+		orders, err := sqlf.Prefix("ORDER BY", sqlf.Join(b.orders, ", ")).BuildTo(ctx)
+		if err != nil {
+			return "", err
+		}
+		built = append(built, orders)
+	}
+	if b.frame != nil {
+		if b.frame.unit == "GROUPS" && !uCtx.Dialect().Capabilities().SupportsGroupsFrame {
+			return "", fmt.Errorf("window: GROUPS frame is not supported by %T", uCtx.BaseDialect())
+		}
+		start, err := b.frame.start.build(ctx)
+		if err != nil {
+			return "", err
+		}
+		end, err := b.frame.end.build(ctx)
+		if err != nil {
+			return "", err
+		}
+		built = append(built, b.frame.unit+" BETWEEN "+start+" AND "+end)
+	}
+	return strings.Join(built, " "), nil
+}
+
+func (f FrameBound) build(ctx sqlf.Context) (string, error) {
+	if f.keyword == "" {
+		return "", fmt.Errorf("window: frame bound not specified")
+	}
+	var offset string
+	switch v := f.offset.(type) {
+	case nil:
+		return f.keyword, nil
+	case sqlf.Builder:
+		built, err := v.BuildTo(ctx)
+		if err != nil {
+			return "", err
+		}
+		offset = built
+	case int:
+		offset = strconv.FormatInt(int64(v), 10)
+	case int64:
+		offset = strconv.FormatInt(v, 10)
+	default:
+		return "", fmt.Errorf("window: unsupported frame offset type %T", f.offset)
+	}
+	return offset + " " + f.keyword, nil
+}
+
+var _ sqlf.Builder = (*namedWindow)(nil)
+
+// namedWindow is a window definition of the WINDOW clause, e.g. `"w" AS (...)`.
+type namedWindow struct {
+	name string
+	spec *WindowBuilder
+}
+
+// BuildTo implements sqlf.Builder
+func (w *namedWindow) BuildTo(ctx sqlf.Context) (string, error) {
+	uCtx, err := contextUpgrade(ctx)
+	if err != nil {
+		return "", err
+	}
+	if !uCtx.Dialect().Capabilities().SupportsWindowClause {
+		return "", fmt.Errorf("window %q: WINDOW clause is not supported by %T", w.name, uCtx.BaseDialect())
+	}
+	if w.spec == nil {
+		return "", fmt.Errorf("window %q: nil spec", w.name)
+	}
+	if w.spec.fn != nil {
+		return "", fmt.Errorf("window %q: unexpected function in spec, use sqlb.NewWindow()", w.name)
+	}
+	name, err := sqlf.Identifier(w.name).BuildTo(ctx)
+	if err != nil {
+		return "", err
+	}
+	spec, err := w.spec.buildSpec(ctx)
+	if err != nil {
+		return "", err
+	}
+	return name + " AS (" + spec + ")", nil
+}
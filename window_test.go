@@ -0,0 +1,157 @@
+package sqlb_test
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/qjebbs/go-sqlb"
+	"github.com/qjebbs/go-sqlb/dialect"
+	"github.com/qjebbs/go-sqlf/v4"
+)
+
+func TestWindowRunningTotal(t *testing.T) {
+	orders := sqlb.NewTable("orders", "o")
+	q := sqlb.NewSelectBuilder().
+		Select(sqlb.Over(sqlf.F("SUM(?)", orders.Column("amount"))).
+			PartitionBy(orders.Column("user_id")).
+			OrderBy(orders.Column("id")).
+			Rows(sqlb.FrameUnboundedPreceding, sqlb.FrameCurrentRow)).
+		From(orders).
+		Where(sqlf.F("? > ?", orders.Column("amount"), 0))
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT SUM("o"."amount") OVER (PARTITION BY "o"."user_id" ORDER BY "o"."id" ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW) FROM "orders" AS "o" WHERE "o"."amount" > $1`
+	wantArgs := []any{0}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestWindowMovingAverage(t *testing.T) {
+	orders := sqlb.NewTable("orders", "o")
+	q := sqlb.NewSelectBuilder().
+		Select(sqlb.Over(sqlf.F("AVG(?)", orders.Column("amount"))).
+			OrderBy(orders.Column("id")).
+			Rows(sqlb.FramePreceding(2), sqlb.FrameFollowing(int64(1)))).
+		From(orders).
+		Where(sqlf.F("? > ?", orders.Column("amount"), 0))
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLServer{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT AVG([o].[amount]) OVER (ORDER BY [o].[id] ROWS BETWEEN 2 PRECEDING AND 1 FOLLOWING) FROM [orders] AS [o] WHERE [o].[amount] > @p1`
+	wantArgs := []any{sql.Named("p1", 0)}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestWindowNamed(t *testing.T) {
+	orders := sqlb.NewTable("orders", "o")
+	q := sqlb.NewSelectBuilder().
+		Select(
+			sqlb.Over(sqlf.F("RANK()")).Window("w"),
+			sqlb.Over(sqlf.F("SUM(?)", orders.Column("amount"))).
+				Window("w").
+				Range(sqlb.FrameUnboundedPreceding, sqlb.FrameCurrentRow),
+		).
+		From(orders).
+		Where(sqlf.F("? > ?", orders.Column("amount"), 0)).
+		Window("w", sqlb.NewWindow().PartitionBy(orders.Column("user_id")).OrderBy(orders.Column("id")))
+	ctx := sqlb.NewContext(context.Background(), dialect.MySQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := "SELECT RANK() OVER `w`, SUM(`o`.`amount`) OVER (`w` RANGE BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW) FROM `orders` AS `o` WHERE `o`.`amount` > ? WINDOW `w` AS (PARTITION BY `o`.`user_id` ORDER BY `o`.`id`)"
+	wantArgs := []any{0}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestWindowKeepsJoin(t *testing.T) {
+	var (
+		orders = sqlb.NewTable("orders", "o")
+		users  = sqlb.NewTable("users", "u")
+	)
+	// the join referenced only by the WINDOW clause is kept
+	q := sqlb.NewSelectBuilder().
+		EnableElimination().
+		Distinct().
+		Select(sqlb.Over(sqlf.F("ROW_NUMBER()")).Window("w")).
+		From(orders).
+		LeftJoin(users, sqlf.F("? = ?", users.Column("id"), orders.Column("user_id"))).
+		Window("w", sqlb.NewWindow().PartitionBy(users.Column("country")))
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLite{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT DISTINCT ROW_NUMBER() OVER "w" FROM "orders" AS "o" LEFT JOIN "users" AS "u" ON "u"."id" = "o"."user_id" WINDOW "w" AS (PARTITION BY "u"."country")`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestWindowGroupsFrame(t *testing.T) {
+	orders := sqlb.NewTable("orders", "o")
+	q := sqlb.NewSelectBuilder().
+		Select(sqlb.Over(sqlf.F("COUNT(*)")).
+			OrderBy(orders.Column("day")).
+			Groups(sqlb.FramePreceding(1), sqlb.FrameCurrentRow)).
+		From(orders)
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLite{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT COUNT(*) OVER (ORDER BY "o"."day" GROUPS BETWEEN 1 PRECEDING AND CURRENT ROW) FROM "orders" AS "o"`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestWindowUnsupported(t *testing.T) {
+	orders := sqlb.NewTable("orders", "o")
+	groups := sqlb.NewSelectBuilder().
+		Select(sqlb.Over(sqlf.F("COUNT(*)")).
+			OrderBy(orders.Column("day")).
+			Groups(sqlb.FramePreceding(1), sqlb.FrameCurrentRow)).
+		From(orders)
+	ctx := sqlb.NewContext(context.Background(), dialect.MySQL{})
+	if _, _, err := groups.Build(ctx); err == nil {
+		t.Error("MySQL GROUPS frame: want error, got nil")
+	}
+	window := sqlb.NewSelectBuilder().
+		Select(sqlb.Over(sqlf.F("RANK()")).Window("w")).
+		From(orders).
+		Window("w", sqlb.NewWindow().OrderBy(orders.Column("id")))
+	ctx = sqlb.NewContext(context.Background(), dialect.Oracle{})
+	if _, _, err := window.Build(ctx); err == nil {
+		t.Error("Oracle WINDOW clause: want error, got nil")
+	}
+}
@@ -0,0 +1,492 @@
+package sqlb_test
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/qjebbs/go-sqlb"
+	"github.com/qjebbs/go-sqlb/dialect"
+	"github.com/qjebbs/go-sqlf/v4"
+)
+
+func TestSelectBuilderWithRecursive(t *testing.T) {
+	var (
+		tree   = sqlb.NewTable("tree")
+		nodes  = sqlb.NewTable("nodes", "n")
+		roots  = sqlb.NewTable("roots")
+		unused = sqlb.NewTable("unused")
+	)
+	anchor := sqlb.NewSelectBuilder().
+		Select(nodes.Columns("id", "parent_id")...).
+		From(nodes).
+		InnerJoin(roots, sqlf.F("? = ?", roots.Column("id"), nodes.Column("id")))
+	recursive := sqlb.NewSelectBuilder().
+		Select(nodes.Columns("id", "parent_id")...).
+		From(nodes).
+		InnerJoin(tree, sqlf.F("? = ?", nodes.Column("parent_id"), tree.Column("id")))
+	q := sqlb.NewSelectBuilder().
+		EnableElimination().
+		With(unused, sqlf.F("SELECT 1")).
+		With(roots, sqlf.F("SELECT id FROM nodes WHERE parent_id = ?", 0)).
+		WithRecursive(tree, []string{"id", "parent_id"}, anchor, recursive).
+		Select(tree.Column("id")).
+		From(tree)
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `WITH RECURSIVE "roots" AS (SELECT id FROM nodes WHERE parent_id = $1), "tree" ("id", "parent_id") AS (SELECT "n"."id", "n"."parent_id" FROM "nodes" AS "n" INNER JOIN "roots" ON "roots"."id" = "n"."id" UNION ALL SELECT "n"."id", "n"."parent_id" FROM "nodes" AS "n" INNER JOIN "tree" ON "n"."parent_id" = "tree"."id") SELECT "tree"."id" FROM "tree"`
+	wantArgs := []any{0}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectBuilderWithRecursiveSQLServer(t *testing.T) {
+	var (
+		tree   = sqlb.NewTable("tree")
+		nodes  = sqlb.NewTable("nodes", "n")
+		roots  = sqlb.NewTable("roots")
+		unused = sqlb.NewTable("unused")
+	)
+	anchor := sqlb.NewSelectBuilder().
+		Select(nodes.Columns("id", "parent_id")...).
+		From(nodes).
+		InnerJoin(roots, sqlf.F("? = ?", roots.Column("id"), nodes.Column("id")))
+	recursive := sqlb.NewSelectBuilder().
+		Select(nodes.Columns("id", "parent_id")...).
+		From(nodes).
+		InnerJoin(tree, sqlf.F("? = ?", nodes.Column("parent_id"), tree.Column("id")))
+	q := sqlb.NewSelectBuilder().
+		EnableElimination().
+		With(unused, sqlf.F("SELECT 1")).
+		With(roots, sqlf.F("SELECT id FROM nodes WHERE parent_id = ?", 0)).
+		WithRecursive(tree, []string{"id", "parent_id"}, anchor, recursive).
+		Select(tree.Column("id")).
+		From(tree)
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLServer{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `WITH [roots] AS (SELECT id FROM nodes WHERE parent_id = @p1), [tree] ([id], [parent_id]) AS (SELECT [n].[id], [n].[parent_id] FROM [nodes] AS [n] INNER JOIN [roots] ON [roots].[id] = [n].[id] UNION ALL SELECT [n].[id], [n].[parent_id] FROM [nodes] AS [n] INNER JOIN [tree] ON [n].[parent_id] = [tree].[id]) SELECT [tree].[id] FROM [tree]`
+	wantArgs := []any{sql.Named("p1", 0)}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectBuilderWithRecursiveEliminated(t *testing.T) {
+	var (
+		foo  = sqlb.NewTable("foo", "f")
+		tree = sqlb.NewTable("tree")
+	)
+	q := sqlb.NewSelectBuilder().
+		EnableElimination().
+		WithRecursive(
+			tree, []string{"n"},
+			sqlf.F("SELECT 1"),
+			sqlb.NewSelectBuilder().
+				Select(sqlf.F("? + 1", tree.Column("n"))).
+				From(tree).
+				Where(sqlf.F("? < ?", tree.Column("n"), 10)),
+		).
+		Select(foo.Column("id")).
+		From(foo)
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `SELECT "f"."id" FROM "foo" AS "f"`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectBuilderWithRecursiveNoColumns(t *testing.T) {
+	tree := sqlb.NewTable("tree")
+	q := sqlb.NewSelectBuilder().
+		WithRecursive(tree, nil, sqlf.F("SELECT 1"), sqlf.F("SELECT n + 1 FROM tree")).
+		Select(tree.Column("n")).
+		From(tree)
+	ctx := sqlb.NewContext(context.Background(), dialect.Oracle{})
+	if _, _, err := q.Build(ctx); err == nil {
+		t.Error("want error for recursive CTE without columns, got nil")
+	}
+	ctx = sqlb.NewContext(context.Background(), dialect.SQLite{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `WITH RECURSIVE "tree" AS (SELECT 1 UNION ALL SELECT n + 1 FROM tree) SELECT "tree"."n" FROM "tree"`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectBuilderWithOptions(t *testing.T) {
+	var (
+		foo    = sqlb.NewTable("foo", "f")
+		counts = sqlb.NewTable("counts")
+	)
+	q := sqlb.NewSelectBuilder().
+		With(counts, sqlf.F("SELECT foo_id, COUNT(*) FROM bar WHERE type = ? GROUP BY foo_id", "x"), sqlb.CTEOptions{
+			Columns:      []string{"foo_id", "cnt"},
+			Materialized: sqlb.CTEMaterialized,
+		}).
+		Select(foo.Column("id"), counts.Column("cnt")).
+		From(foo).
+		InnerJoin(counts, sqlf.F("? = ?", counts.Column("foo_id"), foo.Column("id")))
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `WITH "counts" ("foo_id", "cnt") AS MATERIALIZED (SELECT foo_id, COUNT(*) FROM bar WHERE type = $1 GROUP BY foo_id) SELECT "f"."id", "counts"."cnt" FROM "foo" AS "f" INNER JOIN "counts" ON "counts"."foo_id" = "f"."id"`
+	wantArgs := []any{"x"}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectBuilderWithOptionsSQLite(t *testing.T) {
+	var (
+		foo    = sqlb.NewTable("foo", "f")
+		counts = sqlb.NewTable("counts")
+	)
+	q := sqlb.NewSelectBuilder().
+		With(counts, sqlf.F("SELECT foo_id, COUNT(*) FROM bar WHERE type = ? GROUP BY foo_id", "x"), sqlb.CTEOptions{
+			Columns:      []string{"foo_id", "cnt"},
+			Materialized: sqlb.CTEMaterialized,
+		}).
+		Select(foo.Column("id"), counts.Column("cnt")).
+		From(foo).
+		InnerJoin(counts, sqlf.F("? = ?", counts.Column("foo_id"), foo.Column("id")))
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLite{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `WITH "counts" ("foo_id", "cnt") AS MATERIALIZED (SELECT foo_id, COUNT(*) FROM bar WHERE type = ? GROUP BY foo_id) SELECT "f"."id", "counts"."cnt" FROM "foo" AS "f" INNER JOIN "counts" ON "counts"."foo_id" = "f"."id"`
+	wantArgs := []any{"x"}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectBuilderWithOptionsMySQL(t *testing.T) {
+	var (
+		foo    = sqlb.NewTable("foo", "f")
+		counts = sqlb.NewTable("counts")
+	)
+	q := sqlb.NewSelectBuilder().
+		With(counts, sqlf.F("SELECT foo_id, COUNT(*) FROM bar WHERE type = ? GROUP BY foo_id", "x"), sqlb.CTEOptions{
+			Columns:      []string{"foo_id", "cnt"},
+			Materialized: sqlb.CTEMaterialized,
+		}).
+		Select(foo.Column("id"), counts.Column("cnt")).
+		From(foo).
+		InnerJoin(counts, sqlf.F("? = ?", counts.Column("foo_id"), foo.Column("id")))
+	ctx := sqlb.NewContext(context.Background(), dialect.MySQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the materialization hint is ignored
+	wantQuery := "WITH `counts` (`foo_id`, `cnt`) AS (SELECT foo_id, COUNT(*) FROM bar WHERE type = ? GROUP BY foo_id) SELECT `f`.`id`, `counts`.`cnt` FROM `foo` AS `f` INNER JOIN `counts` ON `counts`.`foo_id` = `f`.`id`"
+	wantArgs := []any{"x"}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectBuilderWithNotMaterialized(t *testing.T) {
+	counts := sqlb.NewTable("counts")
+	q := sqlb.NewSelectBuilder().
+		With(counts, sqlf.F("SELECT 1"), sqlb.CTEOptions{Materialized: sqlb.CTENotMaterialized}).
+		Select(counts.Column("*")).
+		From(counts)
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `WITH "counts" AS NOT MATERIALIZED (SELECT 1) SELECT "counts".* FROM "counts"`
+	var wantArgs []any
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestInsertBuilderFromModifyingCTE(t *testing.T) {
+	var (
+		events = sqlb.NewTable("events")
+		moved  = sqlb.NewTable("moved")
+	)
+	deleted := sqlb.NewDeleteBuilder().
+		DeleteFrom("events").
+		WhereLessThan(events.Column("created"), 100).
+		Returning("*")
+	q := sqlb.NewInsertBuilder().
+		EnableElimination().
+		With(moved, deleted).
+		InsertInto("archive").
+		From(sqlb.NewSelectBuilder().Select(sqlf.F("*")).From(moved))
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `WITH "moved" AS (DELETE FROM "events" WHERE "events"."created" < $1 RETURNING *) INSERT INTO "archive" SELECT * FROM "moved"`
+	wantArgs := []any{100}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestSelectBuilderKeepsModifyingCTE(t *testing.T) {
+	var (
+		events = sqlb.NewTable("events")
+		moved  = sqlb.NewTable("moved")
+		audit  = sqlb.NewTable("audit")
+		unused = sqlb.NewTable("unused")
+	)
+	deleted := sqlb.NewDeleteBuilder().
+		DeleteFrom("events").
+		WhereLessThan(events.Column("created"), 100).
+		Returning("*")
+	logged := sqlb.NewInsertBuilder().
+		InsertInto("audit").
+		From(sqlb.NewSelectBuilder().Select(sqlf.F("*")).From(moved))
+	// the unreferenced modifying CTEs are kept, unlike the plain ones
+	q := sqlb.NewSelectBuilder().
+		EnableElimination().
+		With(moved, deleted).
+		With(audit, logged).
+		With(unused, sqlf.F("SELECT 1")).
+		Select(sqlf.F("1"))
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `WITH "moved" AS (DELETE FROM "events" WHERE "events"."created" < $1 RETURNING *), "audit" AS (INSERT INTO "audit" SELECT * FROM "moved") SELECT 1`
+	wantArgs := []any{100}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestModifyingCTEUnsupported(t *testing.T) {
+	var (
+		events = sqlb.NewTable("events")
+		moved  = sqlb.NewTable("moved")
+	)
+	deleted := sqlb.NewDeleteBuilder().
+		DeleteFrom("events").
+		WhereLessThan(events.Column("created"), 100).
+		Returning("*")
+	q := sqlb.NewSelectBuilder().
+		With(moved, deleted).
+		Select(sqlf.F("*")).
+		From(moved)
+	for _, d := range []dialect.Dialect{
+		dialect.MySQL{},
+		dialect.SQLite{},
+		dialect.SQLServer{},
+		dialect.Oracle{},
+	} {
+		ctx := sqlb.NewContext(context.Background(), d)
+		if _, _, err := q.Build(ctx); err == nil {
+			t.Errorf("%T: want error, got nil", d)
+		}
+	}
+}
+
+func TestUpdateBuilderReturning(t *testing.T) {
+	foo := sqlb.NewTable("foo")
+	q := sqlb.NewUpdateBuilder().
+		Update("foo").
+		Set("a", 1).
+		WhereEquals(foo.Column("id"), 2).
+		Returning("id", "a")
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `UPDATE "foo" SET "a" = $1 WHERE "foo"."id" = $2 RETURNING "id", "a"`
+	wantArgs := []any{1, 2}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestUpdateBuilderReturningSQLServer(t *testing.T) {
+	foo := sqlb.NewTable("foo")
+	q := sqlb.NewUpdateBuilder().
+		Update("foo").
+		Set("a", 1).
+		WhereEquals(foo.Column("id"), 2).
+		Returning("id")
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLServer{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `UPDATE [foo] SET [a] = @p1 OUTPUT INSERTED.[id] WHERE [foo].[id] = @p2`
+	wantArgs := []any{sql.Named("p1", 1), sql.Named("p2", 2)}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestDeleteBuilderReturning(t *testing.T) {
+	foo := sqlb.NewTable("foo")
+	q := sqlb.NewDeleteBuilder().
+		DeleteFrom("foo").
+		WhereEquals(foo.Column("id"), 2).
+		Returning("*")
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLite{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `DELETE FROM "foo" WHERE "foo"."id" = ? RETURNING *`
+	wantArgs := []any{2}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestDeleteBuilderReturningSQLServer(t *testing.T) {
+	foo := sqlb.NewTable("foo")
+	q := sqlb.NewDeleteBuilder().
+		DeleteFrom("foo").
+		WhereEquals(foo.Column("id"), 2).
+		Returning("id")
+	ctx := sqlb.NewContext(context.Background(), dialect.SQLServer{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `DELETE FROM [foo] OUTPUT DELETED.[id] WHERE [foo].[id] = @p1`
+	wantArgs := []any{sql.Named("p1", 2)}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestReturningUnsupported(t *testing.T) {
+	ctx := sqlb.NewContext(context.Background(), dialect.MySQL{})
+	update := sqlb.NewUpdateBuilder().Update("foo").Set("a", 1).Returning("id")
+	if _, _, err := update.Build(ctx); err == nil {
+		t.Error("UPDATE: want error, got nil")
+	}
+	del := sqlb.NewDeleteBuilder().DeleteFrom("foo").Returning("id")
+	if _, _, err := del.Build(ctx); err == nil {
+		t.Error("DELETE: want error, got nil")
+	}
+}
+
+func TestInsertBuilderValuesWithModifyingCTE(t *testing.T) {
+	var (
+		events = sqlb.NewTable("events")
+		moved  = sqlb.NewTable("moved")
+	)
+	q := sqlb.NewInsertBuilder().
+		EnableElimination().
+		With(moved, sqlb.NewDeleteBuilder().
+			DeleteFrom("events").
+			WhereLessThan(events.Column("created"), 100).
+			Returning("id"),
+		).
+		InsertInto("audit").
+		Columns("action").
+		Values("purge")
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	gotQuery, gotArgs, err := q.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuery := `WITH "moved" AS (DELETE FROM "events" WHERE "events"."created" < $1 RETURNING "id") INSERT INTO "audit" ("action") VALUES ($2)`
+	wantArgs := []any{100, "purge"}
+	if wantQuery != gotQuery {
+		t.Errorf("got:\n%s\nwant:\n%s", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(wantArgs, gotArgs) {
+		t.Errorf("want:\n%v\ngot:\n%v", wantArgs, gotArgs)
+	}
+}
+
+func TestModifyingCTENested(t *testing.T) {
+	var (
+		events = sqlb.NewTable("events")
+		moved  = sqlb.NewTable("moved")
+		foo    = sqlb.NewTable("foo", "f")
+	)
+	deleted := sqlb.NewDeleteBuilder().
+		DeleteFrom("events").
+		WhereLessThan(events.Column("created"), 100).
+		Returning("id")
+	sub := sqlb.NewSelectBuilder().
+		With(moved, deleted).
+		Select(moved.Column("id")).
+		From(moved)
+	q := sqlb.NewSelectBuilder().
+		Select(foo.Column("id")).
+		From(foo).
+		Where(sqlf.F("? IN (?)", foo.Column("event_id"), sub))
+	ctx := sqlb.NewContext(context.Background(), dialect.PostgreSQL{})
+	if _, _, err := q.Build(ctx); err == nil {
+		t.Error("want error, got nil")
+	}
+}